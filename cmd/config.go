@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yaoapp/yao/config"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: L("Config tools"),
+	Long:  L("Config tools"),
+}
+
+var configTemplateCmd = &cobra.Command{
+	Use:   "template",
+	Short: L("Print a .env template"),
+	Long:  L("Print a .env template"),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.EnvTemplate(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+var configCheckSeverity = config.SeverityError
+
+var configCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: L("Validate the config and print a JSON report"),
+	Long:  L("Validate the config and print a JSON report"),
+	Run: func(cmd *cobra.Command, args []string) {
+		Boot()
+		report := config.Conf.Report()
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		if report.Failed(configCheckSeverity) {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	configCheckCmd.Flags().StringVarP(&configCheckSeverity, "severity", "s", config.SeverityError, L("Exit with an error at this severity or above (error|warning|info)"))
+	configCmd.AddCommand(configTemplateCmd, configCheckCmd)
+}
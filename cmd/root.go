@@ -48,6 +48,10 @@ var langs = map[string]string{
 	"SessionPort":                           "会话服务端口",
 	"Force migrate":                         "强制更新数据表结构",
 	"Migrate is not allowed on production mode.": "Migrate 不能再生产环境下使用",
+	"Config tools":          "配置工具",
+	"Print a .env template": "输出 .env 配置模板",
+	"Validate the config and print a JSON report":                       "校验配置并输出 JSON 报告",
+	"Exit with an error at this severity or above (error|warning|info)": "存在该级别及以上的问题时返回错误 (error|warning|info)",
 }
 
 // L 多语言切换
@@ -92,6 +96,7 @@ func init() {
 		runCmd,
 		initCmd,
 		serviceCmd,
+		configCmd,
 	)
 	// rootCmd.SetHelpCommand(helpCmd)
 	rootCmd.PersistentFlags().StringVarP(&appPath, "app", "a", "", L("Application directory"))
@@ -116,15 +121,14 @@ func Boot() {
 		}
 		root = r
 	}
+	file := filepath.Join(root, ".env")
 	if envFile != "" {
-		config.Conf = config.LoadFrom(envFile)
-	} else {
-		config.Conf = config.LoadFrom(filepath.Join(root, ".env"))
+		file = envFile
 	}
-
-	if config.Conf.Mode == "production" {
-		config.Production()
-	} else if config.Conf.Mode == "development" {
-		config.Development()
+	cfg := config.LoadApp(file)
+	if err := cfg.Validate(); err != nil {
+		exception.New("Invalid config: %s", 500, err.Error()).Throw()
 	}
+	config.Set(cfg)
+	config.ApplyMode()
 }
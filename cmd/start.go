@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -32,21 +33,30 @@ var startCmd = &cobra.Command{
 		}
 
 		mode := config.Conf.Mode
+
+		// 创建 YAO_MODULE_CREATE_DIRS 中模块的应用目录
+		if err := config.Conf.CreateModuleDirs(); err != nil {
+			fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
+			os.Exit(1)
+		}
+
 		err := engine.Load(config.Conf) // 加载脚本等
 		if err != nil {
 			fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
 			os.Exit(1)
 		}
-		port := fmt.Sprintf(":%d", config.Conf.Port)
-		if port == ":80" {
-			port = ""
-		}
 
-		host := config.Conf.Host
-		if host == "0.0.0.0" {
-			host = "127.0.0.1"
+		// 等待数据库就绪 (YAO_WAIT_FOR_DB)
+		if err := config.Conf.WaitForDependencies(context.Background()); err != nil {
+			fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
+			os.Exit(1)
 		}
 
+		// SIGHUP 时重新打开日志文件并重新加载证书
+		config.InstallSignalHandlers()
+
+		baseURL := config.Conf.BaseURL()
+
 		if mode == "development" {
 			fmt.Println(color.WhiteString("\n---------------------------------"))
 			fmt.Println(color.WhiteString(L("API List")))
@@ -61,7 +71,7 @@ var startCmd = &cobra.Command{
 				for _, p := range api.HTTP.Paths {
 					fmt.Println(
 						colorMehtod(p.Method),
-						color.WhiteString(filepath.Join("/api", api.HTTP.Group, p.Path)),
+						color.WhiteString(config.Conf.Route(filepath.Join("/api", api.HTTP.Group, p.Path))),
 						"\tprocess:", p.Process)
 				}
 			}
@@ -83,9 +93,9 @@ var startCmd = &cobra.Command{
 			fmt.Println(color.WhiteString(L("Root")), color.GreenString(" %s", root))
 		}
 
-		fmt.Println(color.WhiteString(L("Frontend")), color.GreenString(" http://%s%s/", host, port))
-		fmt.Println(color.WhiteString(L("Dashboard")), color.GreenString(" http://%s%s/xiang/login/admin", host, port))
-		fmt.Println(color.WhiteString(L("API")), color.GreenString(" http://%s%s/api", host, port))
+		fmt.Println(color.WhiteString(L("Frontend")), color.GreenString(" %s/", baseURL))
+		fmt.Println(color.WhiteString(L("Dashboard")), color.GreenString(" %s/xiang/login/admin", baseURL))
+		fmt.Println(color.WhiteString(L("API")), color.GreenString(" %s/api", baseURL))
 		fmt.Println(color.WhiteString(L("SessionPort")), color.GreenString(" %d", share.SessionPort))
 		fmt.Println(color.WhiteString(L("Listening")), color.GreenString(" %s:%d", config.Conf.Host, config.Conf.Port))
 
@@ -113,8 +123,14 @@ var startCmd = &cobra.Command{
 			}
 		}
 
+		// 定时检查配置文件 (YAO_RELOAD_INTERVAL)
+		go config.PollReload(context.Background())
+
 		fmt.Println(color.GreenString(L("✨LISTENING✨")))
-		service.Start()
+		if err := service.Start(); err != nil {
+			fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
+			os.Exit(1)
+		}
 	},
 }
 
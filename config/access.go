@@ -0,0 +1,73 @@
+package config
+
+import (
+	"reflect"
+	"time"
+)
+
+// GetString 读取配置项 (按环境变量名称, 如 YAO_HOST), 非字符串配置项返回环境变量格式的值, 未知配置项返回空字符串
+func (c Config) GetString(name string) string {
+	field, ok := c.lookup(name)
+	if !ok {
+		return ""
+	}
+	if field.Value.Kind() == reflect.String {
+		return field.Value.String()
+	}
+	return field.text()
+}
+
+// GetInt 读取整数配置项 (如 YAO_PORT), 类型不符或未知配置项返回 0
+func (c Config) GetInt(name string) int {
+	field, ok := c.lookup(name)
+	if !ok {
+		return 0
+	}
+	switch field.Value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(field.Value.Int())
+	}
+	return 0
+}
+
+// GetBool 读取布尔配置项 (如 YAO_STRICT), 类型不符或未知配置项返回 false
+func (c Config) GetBool(name string) bool {
+	field, ok := c.lookup(name)
+	if !ok || field.Value.Kind() != reflect.Bool {
+		return false
+	}
+	return field.Value.Bool()
+}
+
+// GetDuration 读取时长配置项 (如 YAO_DRAIN_TIMEOUT), 类型不符或未知配置项返回 0
+func (c Config) GetDuration(name string) time.Duration {
+	field, ok := c.lookup(name)
+	if !ok || field.Value.Type() != reflect.TypeOf(time.Duration(0)) {
+		return 0
+	}
+	return time.Duration(field.Value.Int())
+}
+
+// GetStrings 读取列表配置项 (如 YAO_DB_PRIMARY), 返回副本, 类型不符或未知配置项返回 nil
+func (c Config) GetStrings(name string) []string {
+	field, ok := c.lookup(name)
+	if !ok || field.Value.Kind() != reflect.Slice || field.Value.Type().Elem().Kind() != reflect.String {
+		return nil
+	}
+	values := make([]string, field.Value.Len())
+	for i := range values {
+		values[i] = field.Value.Index(i).String()
+	}
+	return values
+}
+
+// lookup 按环境变量名称 (YAO_ 前缀或 SetEnvPrefix 设定的前缀) 查找配置项, 并记录读取
+func (c Config) lookup(name string) (configField, bool) {
+	for _, field := range c.fields() {
+		if field.Env == name || envVarName(field.Env) == name {
+			recordAccess(field.Env)
+			return field, true
+		}
+	}
+	return configField{}, false
+}
@@ -0,0 +1,13 @@
+//go:build !configtrace
+// +build !configtrace
+
+package config
+
+// recordAccess 未使用 configtrace 编译标签时不记录
+func recordAccess(name string) {}
+
+// UnusedFields 已设置为非默认值, 但进程运行期间从未通过 Get* 方法读取的配置项 (环境变量名称, 按名称排序)
+// 仅在使用 configtrace 编译标签时记录读取, 否则返回 nil
+func UnusedFields() []string {
+	return nil
+}
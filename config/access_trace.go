@@ -0,0 +1,37 @@
+//go:build configtrace
+// +build configtrace
+
+package config
+
+import (
+	"sort"
+	"sync"
+)
+
+// accessed 通过 Get* 方法读取过的配置项 (环境变量名称)
+var accessed = map[string]bool{}
+var accessedMutex sync.Mutex
+
+// recordAccess 记录配置项读取
+func recordAccess(name string) {
+	accessedMutex.Lock()
+	accessed[name] = true
+	accessedMutex.Unlock()
+}
+
+// UnusedFields 已设置为非默认值, 但进程运行期间从未通过 Get* 方法读取的配置项 (环境变量名称, 按名称排序)
+// 仅在使用 configtrace 编译标签时记录读取, 否则返回 nil
+func UnusedFields() []string {
+	nonDefault := Current().NonDefault()
+	accessedMutex.Lock()
+	defer accessedMutex.Unlock()
+
+	unused := []string{}
+	for _, field := range (&Config{}).fields() {
+		if _, has := nonDefault[envVarName(field.Env)]; has && !accessed[field.Env] {
+			unused = append(unused, envVarName(field.Env))
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
@@ -0,0 +1,28 @@
+//go:build configtrace
+// +build configtrace
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnusedFields(t *testing.T) {
+	prev := Conf
+	defer func() {
+		Conf = prev
+		publishConf()
+	}()
+
+	Conf = DefaultConfig()
+	Conf.PublicHost = "unused.yaoapps.com"
+	Conf.Workers = 4
+	publishConf()
+
+	assert.Contains(t, UnusedFields(), "YAO_PUBLIC_HOST")
+	assert.Equal(t, 4, Current().GetInt("YAO_WORKERS"))
+	assert.NotContains(t, UnusedFields(), "YAO_WORKERS")
+	assert.Contains(t, UnusedFields(), "YAO_PUBLIC_HOST")
+}
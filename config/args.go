@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yaoapp/kun/log"
+)
+
+var argKeyRe = regexp.MustCompile(`^[A-Z][A-Z0-9_.]*$`)
+
+// LoadFromArgs 从 KEY=VALUE 形式的命令行参数加载配置 (覆盖环境变量配置)
+// KEY 可以是环境变量名称 (YAO_PORT), 省略前缀的名称 (PORT), 或使用 XIANG_ 前缀 (XIANG_PORT)
+// 非赋值参数将被忽略; 格式错误或无法识别的参数输出警告, 严格模式下返回错误
+func LoadFromArgs(args []string) (Config, error) {
+	vars := environ()
+	keys := argKeys()
+	invalid := []string{}
+
+	for _, arg := range args {
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) != 2 {
+			if argKeyRe.MatchString(arg) {
+				invalid = append(invalid, fmt.Sprintf("%s (missing =)", arg))
+			}
+			continue
+		}
+
+		name, has := keys[strings.ToUpper(strings.TrimSpace(kv[0]))]
+		if !has {
+			invalid = append(invalid, fmt.Sprintf("%s (unknown key)", kv[0]))
+			continue
+		}
+		vars[name] = kv[1]
+	}
+
+	cfg, err := parse(vars)
+	if err != nil {
+		return cfg, err
+	}
+
+	if len(invalid) > 0 {
+		if cfg.Strict {
+			return cfg, fmt.Errorf("invalid config arguments: %s", strings.Join(invalid, ", "))
+		}
+		for _, arg := range invalid {
+			log.Warn("Ignore config argument %s", arg)
+		}
+	}
+	return cfg, nil
+}
+
+// argKeys 参数名称与环境变量名称对照表
+func argKeys() map[string]string {
+	keys := map[string]string{}
+	for _, field := range (&Config{}).fields() {
+		short := strings.TrimPrefix(field.Env, "YAO_")
+		keys[field.Env] = field.Env
+		keys[envVarName(field.Env)] = field.Env
+		keys[short] = field.Env
+		keys["XIANG_"+short] = field.Env
+		keys[strings.ToUpper(strings.ReplaceAll(field.Name, ".", "_"))] = field.Env
+	}
+	return keys
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yaoapp/kun/log"
+)
+
+// auditRecord 审计日志记录
+type auditRecord struct {
+	Time   string `json:"time"`
+	Actor  string `json:"actor"`
+	Action string `json:"action"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// auditFile 审计日志文件 (只追加写入)
+var auditFile *os.File
+var auditMutex sync.Mutex
+
+// AuditEvent 写入审计日志 (YAO_AUDIT_LOG 文件, 每行一条 JSON 记录; 未设置或无法写入时写入服务日志)
+func AuditEvent(actor, action, detail string) {
+	confMutex.RLock()
+	dest := strings.TrimSpace(Conf.AuditLog)
+	confMutex.RUnlock()
+
+	record := auditRecord{
+		Time:   currentClock().Now().UTC().Format(time.RFC3339Nano),
+		Actor:  actor,
+		Action: action,
+		Detail: detail,
+	}
+
+	if dest != "" {
+		err := writeAudit(dest, record)
+		if err == nil {
+			return
+		}
+		log.With(log.F{"file": dest}).Error("can't write audit log. %s", err.Error())
+	}
+	log.With(log.F{"audit": true, "actor": actor, "action": action}).Info(detail)
+}
+
+// writeAudit 追加写入审计日志文件 (文件地址变更时重新打开)
+func writeAudit(dest string, record auditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	auditMutex.Lock()
+	defer auditMutex.Unlock()
+
+	file, err := filepath.Abs(dest)
+	if err != nil {
+		return err
+	}
+	if auditFile == nil || auditFile.Name() != file {
+		if auditFile != nil {
+			auditFile.Close()
+			auditFile = nil
+		}
+		if err := os.MkdirAll(filepath.Dir(file), os.ModePerm); err != nil {
+			return err
+		}
+		auditFile, err = os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = auditFile.Write(append(data, '\n'))
+	return err
+}
+
+// auditChanges 记录配置变更审计日志 (变更的配置项名称)
+func auditChanges(action string, prev, cfg Config, detail string) {
+	changed := prev.Diff(cfg)
+	if detail != "" {
+		detail += "; "
+	}
+	if len(changed) == 0 {
+		detail += "no changes"
+	} else {
+		detail += "changed: " + strings.Join(changed, ",")
+	}
+	AuditEvent("system", action, detail)
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+)
+
+// minRSAKeyBits 生产环境证书 RSA 密钥最小长度
+const minRSAKeyBits = 2048
+
+// weakSignatureAlgorithms 不安全的证书签名算法
+var weakSignatureAlgorithms = map[x509.SignatureAlgorithm]bool{
+	x509.MD2WithRSA:    true,
+	x509.MD5WithRSA:    true,
+	x509.SHA1WithRSA:   true,
+	x509.DSAWithSHA1:   true,
+	x509.ECDSAWithSHA1: true,
+}
+
+// certIssues 生产环境 HTTPS 证书问题 (自签名证书, 不安全的签名算法或密钥长度)
+// 使用私有 CA 的内部部署可能合理使用这类证书, 仅在严格模式 (YAO_STRICT) 下视为错误
+func (c Config) certIssues() error {
+	if c.Mode != "production" || !c.HTTPS() {
+		return nil
+	}
+
+	leaf, err := c.leafCert()
+	if err != nil {
+		return nil // 证书读取错误由 CheckTLS 报告
+	}
+
+	errs := Errors{}
+	if bytes.Equal(leaf.RawIssuer, leaf.RawSubject) {
+		errs = append(errs, fmt.Errorf("YAO_CERT %s is self-signed, use a certificate issued by a trusted CA in production", c.Cert))
+	}
+	if weakSignatureAlgorithms[leaf.SignatureAlgorithm] {
+		errs = append(errs, fmt.Errorf("YAO_CERT %s uses the weak signature algorithm %s, reissue it with SHA-256 or better", c.Cert, leaf.SignatureAlgorithm))
+	}
+	if key, ok := leaf.PublicKey.(*rsa.PublicKey); ok && key.N.BitLen() < minRSAKeyBits {
+		errs = append(errs, fmt.Errorf("YAO_CERT %s uses a %d-bit RSA key, at least %d bits are required", c.Cert, key.N.BitLen(), minRSAKeyBits))
+	}
+	return errs.Err()
+}
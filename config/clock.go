@@ -0,0 +1,51 @@
+package config
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock 时钟 (依赖时间的功能通过时钟读取时间, 测试时可替换)
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker 定时器
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// realClock 系统时钟
+type realClock struct{}
+
+// realTicker 系统定时器
+type realTicker struct{ *time.Ticker }
+
+var clock Clock = realClock{}
+var clockMutex sync.RWMutex
+
+// SetClock 设定时钟 (用于测试, nil 恢复系统时钟)
+func SetClock(c Clock) {
+	if c == nil {
+		c = realClock{}
+	}
+	clockMutex.Lock()
+	clock = c
+	clockMutex.Unlock()
+}
+
+// currentClock 当前时钟
+func currentClock() Clock {
+	clockMutex.RLock()
+	defer clockMutex.RUnlock()
+	return clock
+}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) NewTicker(d time.Duration) Ticker       { return realTicker{time.NewTicker(d)} }
+
+func (t realTicker) C() <-chan time.Time { return t.Ticker.C }
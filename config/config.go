@@ -2,8 +2,12 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 
 	"github.com/caarlos0/env/v6"
 	"github.com/gin-gonic/gin"
@@ -12,112 +16,447 @@ import (
 	"github.com/yaoapp/kun/log"
 )
 
-// Conf 配置参数
+// Conf 配置参数 (运行期间可能被替换, 并发读写请使用 Get, Set 或 Current)
 var Conf Config
 
+// confMutex 配置读写锁 (替换 Conf 时使用)
+var confMutex sync.RWMutex
+
+// writeMutex 配置写入锁: 修改配置的操作 (Reload, Update, Set, 切换运行模式等) 从读取当前配置到替换完成期间持有, 并发修改不会丢失
+// 应用运行模式及通知配置变更 (OnReload) 在释放锁后执行, 处理器中可以继续修改配置
+var writeMutex sync.Mutex
+
+// envFile 已加载的配置文件
+var envFile string
+
+// envOverlays 叠加在 envFile 之上的配置文件 (如 .env.local, 文件不存在时忽略)
+var envOverlays []string
+
+// logMutex 日志输出锁 (打开, 重新打开及关闭日志时使用, 保护以下日志输出状态)
+var logMutex sync.Mutex
+
 // LogOutput 日志输出
 var LogOutput *os.File // 日志文件
 
+// logSink 非文件日志输出 (如 systemd journal)
+var logSink io.WriteCloser
+
+// logStderrWrapped 是否已使用处理后的 stderr 作为日志输出
+var logStderrWrapped bool
+
+// logOpenedDest 当前打开的日志地址 (未打开或输出到 stderr 时为空)
+var logOpenedDest string
+
+// logPipe 命名管道日志输出 (日志地址为命名管道时使用)
+var logPipe *pipeWriter
+
+// logStd 日志地址为 stdout 或 stderr 时的输出
+var logStd *stdWriter
+
+// logRotate 轮转日志输出 (设置 YAO_LOG_MAX_SIZE 等轮转配置时使用)
+var logRotate *rotateWriter
+
 func init() {
+	OnReload(dumpConfig)
+	OnReload(reloadCert)
+	OnReload(notifyReloaded)
+	loadDefault()
+}
+
+// loadDefault 加载工作目录下的 .env (包初始化时使用)
+// 仅输出静态检查发现的问题, 不访问应用目录; 完整校验 (Validate) 在启动时执行
+func loadDefault() {
 	filename, _ := filepath.Abs(filepath.Join(".", ".env"))
 	if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) {
 		Conf = Load()
-		return
+	} else {
+		Conf = LoadApp(filename)
 	}
-	Conf = LoadFrom(filename)
-	if Conf.Mode == "production" {
-		Production()
-	} else if Conf.Mode == "development" {
-		Development()
+	warnStatic(Conf)
+	ApplyMode()
+	checkTimezone(Conf)
+	publishConf()
+	dumpConfig(Conf)
+	loaded = false // 包初始化时的加载不计入, 嵌入应用仍可调用 SetEnvPrefix 后重新加载
+}
+
+// warnStatic 将静态检查发现的问题输出到日志 (不中止启动)
+func warnStatic(cfg Config) {
+	for _, err := range issueList(cfg.validateStatic()) {
+		log.Warn("%s", err.Error())
 	}
 }
 
 // LoadFrom 从配置项中加载
-func LoadFrom(envfile string) Config {
+// overlays 为依次叠加的配置文件 (后加载的覆盖先加载的, 文件不存在时忽略)
+func LoadFrom(envfile string, overlays ...string) Config {
 
 	file, err := filepath.Abs(envfile)
 	if err != nil {
 		log.Warn("Can't load env file. %s", err.Error())
 	}
+	envFile = file
 	err = godotenv.Overload(file)
 	if err != nil {
 		log.Warn("Can't load env file. %s", err.Error())
 	}
+	recordEnvModTime(file)
+
+	envOverlays = []string{}
+	for _, overlay := range overlays {
+		if overlay, err := filepath.Abs(overlay); err == nil {
+			envOverlays = append(envOverlays, overlay)
+		}
+	}
+	if err := overloadOverlays(envOverlays); err != nil {
+		log.Warn("Can't load env file. %s", err.Error())
+	}
 
 	return Load()
 }
 
-// Load 加载配置
+// LoadApp 加载应用配置文件及其本地配置 (envfile 加 .local 后缀, 如 .env.local, 覆盖 envfile 中的配置, 不存在时忽略)
+// 包初始化及命令行启动时使用; Reload 重新加载时同样叠加本地配置
+func LoadApp(envfile string) Config {
+	return LoadFrom(envfile, envfile+".local")
+}
+
+// overloadOverlays 依次加载叠加的配置文件 (文件不存在时忽略)
+func overloadOverlays(overlays []string) error {
+	for _, overlay := range overlays {
+		if _, err := os.Stat(overlay); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err := godotenv.Overload(overlay); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadFromReader 从 r 读取 .env 格式配置, 写入环境变量后加载配置 (不读写磁盘文件, 可用于读取解密后的配置)
+// 与 LoadFrom 相同, r 中的配置项覆盖已有的环境变量
+func LoadFromReader(r io.Reader) (Config, error) {
+	vars, err := godotenv.Parse(r)
+	if err != nil {
+		return Config{}, fmt.Errorf("config is not a valid env file: %s", err.Error())
+	}
+	for key, value := range vars {
+		os.Setenv(key, value)
+	}
+
+	markLoaded()
+	return parseValidEnv()
+}
+
+// Load 加载配置 (读取失败时抛出异常, 需要自行处理错误时请使用 LoadE)
 func Load() Config {
-	cfg := Config{}
-	if err := env.Parse(&cfg); err != nil {
+	cfg, err := LoadE()
+	if err != nil {
 		exception.New("Can't read config %s", 500, err.Error()).Throw()
 	}
-	cfg.Root, _ = filepath.Abs(cfg.Root)
 	return cfg
 }
 
+// LoadE 加载配置, 读取失败时返回错误 (不抛出异常, 供嵌入 Yao 的应用自行处理)
+func LoadE() (Config, error) {
+	markLoaded()
+	return parseEnv()
+}
+
+// parse 根据给定的环境变量解析配置 (替换内置变量及引用的环境变量, 应用根目录转换为绝对路径)
+func parse(vars map[string]string) (Config, error) {
+	cfg, err := parseVars(vars)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.interpolate(vars)
+	cfg.Root, _ = filepath.Abs(cfg.Root)
+	return cfg, nil
+}
+
+// parseVars 根据给定的环境变量解析配置 (无副作用, 各加载方法均基于此解析)
+func parseVars(vars map[string]string) (Config, error) {
+	cfg := Config{}
+	if err := env.Parse(&cfg, env.Options{Environment: vars}); err != nil {
+		return cfg, err
+	}
+	cfg.applyBuildInfo()
+	return cfg, nil
+}
+
+// ParseConfig 根据给定的环境变量解析并校验配置, 返回配置及解析或校验错误
+// 不读取环境变量及文件, 不输出日志, 结果只取决于参数 (不替换内置变量, 应用根目录保持原值), 可用于模糊测试
+func ParseConfig(vars map[string]string) (Config, error) {
+	cfg, err := parseVars(vars)
+	if err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.validateStatic()
+}
+
+// ApplyMode 根据当前配置的运行模式 (YAO_ENV) 设定运行环境 (未知的运行模式输出警告)
+func ApplyMode() {
+	mode := Get().Mode
+	switch mode {
+	case "production":
+		Production()
+	case "development":
+		Development()
+	case "test":
+		Test()
+	default:
+		log.Warn("Unknown YAO_ENV %q, expected one of %s", mode, strings.Join(modes, ", "))
+	}
+}
+
 // Production 设定为生产环境
 func Production() {
-	Conf.Mode = "production"
-	log.SetLevel(log.ErrorLevel)
-	log.SetFormatter(log.TEXT)
-	if Conf.LogMode == "JSON" {
-		log.SetFormatter(log.JSON)
-	}
-	gin.SetMode(gin.ReleaseMode)
-	ReloadLog()
+	setRunMode("production", log.ErrorLevel, gin.ReleaseMode)
 }
 
 // Development 设定为开发环境
 func Development() {
-	Conf.Mode = "development"
-	log.SetLevel(log.TraceLevel)
-	log.SetFormatter(log.TEXT)
-	if Conf.LogMode == "JSON" {
-		log.SetFormatter(log.JSON)
-	}
-	gin.SetMode(gin.DebugMode)
+	setRunMode("development", log.TraceLevel, gin.DebugMode)
+}
+
+// Test 设定为测试环境 (用于 CI 等自动化测试)
+func Test() {
+	setRunMode("test", log.WarnLevel, gin.TestMode)
+}
+
+// modeMutex 运行模式锁 (日志级别, gin 运行模式为全局状态, 并发修改配置时依次设定)
+var modeMutex sync.Mutex
+
+// setRunMode 设定运行模式, 日志级别及 gin 运行模式, 并重新打开日志
+func setRunMode(mode string, level log.Level, ginMode string) {
+	modeMutex.Lock()
+	defer modeMutex.Unlock()
+	setMode(mode)
+	log.SetLevel(level)
+	setLogFormat()
+	gin.SetMode(ginMode)
 	ReloadLog()
 }
 
-// ReloadLog 重新打开日志
+// setMode 设定运行模式 (加写锁修改 Conf, 非生产环境生成临时密钥) 并发布配置
+func setMode(mode string) {
+	writeMutex.Lock()
+	defer writeMutex.Unlock()
+	confMutex.Lock()
+	Conf.Mode = mode
+	Conf.FillDevSecrets()
+	cfg := Conf
+	confMutex.Unlock()
+	publish(cfg)
+}
+
+// ReloadLog 重新打开日志 (日志地址未变更时保留当前文件句柄, 仅更新日志处理配置)
 func ReloadLog() {
-	CloseLog()
-	OpenLog()
+	cfg := Get()
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	if dests := cfg.logDestinations(); len(dests) > 0 && dests[0] == logOpenedDest {
+		keepLog(cfg)
+		return
+	}
+	closeLog()
+	openLog(cfg)
 }
 
-// OpenLog 打开日志
-func OpenLog() {
-	if Conf.Log != "" {
-		logfile, err := filepath.Abs(Conf.Log)
-		if err != nil {
-			log.With(log.F{"file": logfile}).Error(err.Error())
+// ReopenLog 关闭并重新打开日志 (日志文件被外部工具轮转或删除后使用, 未设置日志地址时不做处理)
+func ReopenLog() {
+	cfg := Get()
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	closeLog()
+	openLog(cfg)
+}
+
+// keepLog 使用当前配置重新包装已打开的日志输出 (调用方须持有 logMutex)
+func keepLog(cfg Config) {
+	if logSink != nil {
+		log.SetOutput(newLogWriter(logSink, cfg))
+		return
+	}
+	if logPipe != nil {
+		logPipe.mutex.Lock()
+		logPipe.reconnect = cfg.LogPipeReconnect
+		logPipe.mutex.Unlock()
+		log.SetOutput(newLogWriter(logPipe, cfg))
+		return
+	}
+	if logStd != nil {
+		log.SetOutput(newLogWriter(logStd, cfg))
+		return
+	}
+	if logRotate != nil {
+		logRotate.configure(cfg)
+		log.SetOutput(newLogWriter(logRotate, cfg))
+		return
+	}
+	if cfg.rotationEnabled() {
+		if rotate, err := newRotateWriter(LogOutput, cfg); err == nil {
+			logRotate = rotate
+			log.SetOutput(newLogWriter(logRotate, cfg))
+			gin.DefaultWriter = logRotate
 			return
 		}
+	}
+	log.SetOutput(newLogWriter(LogOutput, cfg))
+}
+
+// OpenLog 打开日志 (依次尝试 YAO_LOG 及 YAO_LOG_FALLBACKS, 使用第一个可用的日志地址)
+func OpenLog() {
+	cfg := Get()
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	openLog(cfg)
+}
 
-		logpath := filepath.Dir(logfile)
-		if _, err := os.Stat(logpath); os.IsNotExist(err) {
-			if err := os.MkdirAll(logpath, os.ModePerm); err != nil {
-				log.With(log.F{"file": logfile}).Error(err.Error())
-				return
+// openLog 按给定配置打开日志 (调用方须持有 logMutex)
+func openLog(cfg Config) {
+	dests := cfg.logDestinations()
+	for i, dest := range dests {
+		err := openLogDestination(dest, cfg)
+		if err == nil {
+			logOpenedDest = dest
+			if i > 0 {
+				log.Warn("Log destination %s is not available, log to %s instead", dests[0], dest)
 			}
+			return
+		}
+		log.With(log.F{"dest": dest}).Error(err.Error())
+	}
+
+	if len(dests) > 0 {
+		log.Warn("No log destination is available, log to stderr instead")
+	}
+	openStderrLog(cfg)
+}
+
+// logDestinations 日志地址列表 (YAO_LOG 及 YAO_LOG_FALLBACKS)
+func (c Config) logDestinations() []string {
+	dests := []string{}
+	for _, dest := range append([]string{c.Log}, c.LogFallbacks...) {
+		dest = strings.TrimSpace(dest)
+		if dest != "" {
+			dests = append(dests, dest)
+		}
+	}
+	return dests
+}
+
+// openLogDestination 打开日志地址 (文件, journal://, stdout 或 stderr)
+func openLogDestination(dest string, cfg Config) error {
+	d, err := ParseLogDestination(dest)
+	if err != nil {
+		return err
+	}
+
+	switch d.Kind {
+	case LogDestJournal:
+		return openJournalLog(d.Path, cfg)
+	case LogDestStdout, LogDestStderr:
+		logStd = newStdWriter(os.Stdout)
+		if d.Kind == LogDestStderr {
+			logStd = newStdWriter(os.Stderr)
 		}
-		LogOutput, err = os.OpenFile(logfile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		log.SetOutput(newLogWriter(logStd, cfg))
+		gin.DefaultWriter = logStd
+		return nil
+	}
+
+	logfile, err := filepath.Abs(d.Path)
+	if err != nil {
+		return err
+	}
+
+	logpath := filepath.Dir(logfile)
+	if _, err := os.Stat(logpath); os.IsNotExist(err) {
+		if err := os.MkdirAll(logpath, os.ModePerm); err != nil {
+			return err
+		}
+	}
+
+	output, err := os.OpenFile(logfile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	LogOutput = output
+	if isNamedPipe(output) {
+		logPipe = newPipeWriter(output, cfg.LogPipeReconnect)
+		log.SetOutput(newLogWriter(logPipe, cfg))
+		gin.DefaultWriter = logPipe
+		return nil
+	}
+	if cfg.rotationEnabled() {
+		rotate, err := newRotateWriter(output, cfg)
 		if err != nil {
-			log.With(log.F{"file": logfile}).Error(err.Error())
-			return
+			output.Close()
+			return err
 		}
+		logRotate = rotate
+		log.SetOutput(newLogWriter(logRotate, cfg))
+		gin.DefaultWriter = logRotate
+		return nil
+	}
+	log.SetOutput(newLogWriter(LogOutput, cfg))
+	gin.DefaultWriter = LogOutput
+	return nil
+}
 
-		log.SetOutput(LogOutput)
-		gin.DefaultWriter = LogOutput
+// openStderrLog 日志输出到 stderr (需要处理日志输出时包装 stderr)
+func openStderrLog(cfg Config) {
+	if cfg.logFiltered() {
+		log.SetOutput(newLogWriter(os.Stderr, cfg))
+		logStderrWrapped = true
+	} else if logStderrWrapped {
+		log.SetOutput(os.Stderr)
+		logStderrWrapped = false
 	}
 }
 
 // CloseLog 关闭日志
 func CloseLog() {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	closeLog()
+}
+
+// closeLog 关闭日志 (调用方须持有 logMutex)
+func closeLog() {
+	logOpenedDest = ""
+	logStd = nil
+	if logSink != nil {
+		if err := logSink.Close(); err != nil {
+			log.Error(err.Error())
+		}
+		logSink = nil
+	}
+
+	if logPipe != nil {
+		if err := logPipe.Close(); err != nil {
+			log.Error(err.Error())
+		}
+		logPipe = nil
+		LogOutput = nil
+		return
+	}
+
+	if logRotate != nil {
+		if err := logRotate.Close(); err != nil {
+			log.Error(err.Error())
+		}
+		logRotate = nil
+		LogOutput = nil
+		return
+	}
+
 	if LogOutput != nil {
 		err := LogOutput.Close()
+		LogOutput = nil
 		if err != nil {
 			log.Error(err.Error())
 			return
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yaoapp/kun/log"
+)
+
+// LoadFromConfigMapDir 从 Kubernetes ConfigMap 挂载目录加载配置
+// 文件名为环境变量名称 (例: YAO_PORT), 文件内容 (去除首尾空白) 为配置值, 写入环境变量后加载配置
+// 忽略子目录及 . 开头的文件 (如 Kubernetes 使用的 ..data 链接), 非配置项文件输出警告后忽略
+func LoadFromConfigMapDir(dir string) (Config, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return Config{}, err
+	}
+
+	names := map[string]bool{}
+	for _, field := range (&Config{}).fields() {
+		names[envVarName(field.Env)] = true
+	}
+
+	vars := map[string]string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		// ConfigMap 的每个配置项都是指向 ..data 目录的链接, 读取链接目标
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("can't read config file %s: %s", path, err.Error())
+		}
+		if info.IsDir() {
+			continue
+		}
+		if !names[name] {
+			log.Warn("Ignore %s, %s is not a config name", path, name)
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("can't read config file %s: %s", path, err.Error())
+		}
+		vars[name] = strings.TrimSpace(string(data))
+	}
+
+	for name, value := range vars {
+		os.Setenv(name, value)
+	}
+
+	markLoaded()
+	return parseValidEnv()
+}
@@ -0,0 +1,52 @@
+package config
+
+import "sync/atomic"
+
+// current 当前配置的只读副本 (*Config), 配置变更时整体替换
+var current atomic.Value
+
+// Current 返回当前配置的只读副本, 读取时无需加锁 (适用于频繁读取配置的场景)
+// 配置变更时替换为新的副本, 已取得的副本保持不变; 调用方不应修改返回的配置
+// 注意: 直接赋值 Conf 不会更新 Current, 请使用 Reload, Update 等方法修改配置
+func Current() *Config {
+	if cfg, ok := current.Load().(*Config); ok {
+		return cfg
+	}
+	confMutex.RLock()
+	cfg := Conf.clone()
+	confMutex.RUnlock()
+	return &cfg
+}
+
+// Get 返回当前配置的副本 (加读锁读取, 修改副本不影响当前配置)
+func Get() Config {
+	confMutex.RLock()
+	defer confMutex.RUnlock()
+	return Conf.clone()
+}
+
+// Set 替换当前配置 (加写锁替换, 并发布供 Current 读取)
+// 不校验配置, 也不通知配置变更; 需要校验及通知时请使用 Update 或 Reload
+func Set(cfg Config) {
+	cfg = cfg.clone()
+	writeMutex.Lock()
+	defer writeMutex.Unlock()
+	confMutex.Lock()
+	Conf = cfg
+	confMutex.Unlock()
+	publish(cfg)
+}
+
+// publish 发布配置副本 (供 Current 读取)
+func publish(cfg Config) {
+	cp := cfg.clone()
+	current.Store(&cp)
+}
+
+// publishConf 发布当前配置
+func publishConf() {
+	confMutex.RLock()
+	cfg := Conf
+	confMutex.RUnlock()
+	publish(cfg)
+}
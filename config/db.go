@@ -0,0 +1,254 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// dsnDriver 根据 DSN 格式推断数据库驱动 (无法判断返回空字符串)
+func dsnDriver(dsn string) string {
+	dsn = strings.TrimSpace(dsn)
+	lower := strings.ToLower(dsn)
+	switch {
+	case strings.HasPrefix(lower, "postgres://"), strings.HasPrefix(lower, "postgresql://"):
+		return "postgres"
+	case strings.HasPrefix(lower, "mysql://"), strings.Contains(lower, "@tcp("), strings.Contains(lower, "@unix("):
+		return "mysql"
+	case strings.HasPrefix(lower, "file:"), strings.HasPrefix(lower, "sqlite://"), strings.HasPrefix(lower, "sqlite3://"),
+		strings.HasSuffix(lower, ".db"), strings.HasSuffix(lower, ".sqlite"), strings.HasSuffix(lower, ".sqlite3"):
+		return "sqlite3"
+	case strings.Contains(lower, "dbname="), strings.Contains(lower, "host=") && strings.Contains(lower, " "):
+		return "postgres"
+	}
+	return ""
+}
+
+// validateDB 检查数据库配置 (从库 DSN 与驱动是否一致, sqlite3 参数, TLS 设置)
+func (c Config) validateDB() error {
+	errs := Errors{}
+	for i, dsn := range c.DB.Secondary {
+		driver := dsnDriver(dsn)
+		if driver != "" && driver != c.DB.Driver {
+			errs = append(errs, fmt.Errorf("YAO_DB_SECONDARY[%d] looks like a %s DSN, but YAO_DB_DRIVER is %s", i, driver, c.DB.Driver))
+		}
+	}
+
+	if mode := strings.ToUpper(strings.TrimSpace(c.DB.SQLiteJournalMode)); mode != "" && !contains(sqliteJournalModes, mode) {
+		errs = append(errs, fmt.Errorf("YAO_DB_SQLITE_JOURNAL_MODE must be one of %s (got %q)", strings.Join(sqliteJournalModes, ", "), c.DB.SQLiteJournalMode))
+	}
+	if c.DB.SQLiteBusyTimeout < 0 {
+		errs = append(errs, fmt.Errorf("YAO_DB_SQLITE_BUSY_TIMEOUT must not be negative (got %s)", c.DB.SQLiteBusyTimeout))
+	}
+
+	if c.DB.RequireTLS {
+		for i, dsn := range c.DB.Primary {
+			if !dsnTLS(c.DB.Driver, dsn) {
+				errs = append(errs, fmt.Errorf("YAO_DB_PRIMARY[%d] does not enable TLS, but YAO_DB_REQUIRE_TLS is set (%s)", i, tlsHint(c.DB.Driver)))
+			}
+		}
+		for i, dsn := range c.DB.Secondary {
+			if !dsnTLS(c.DB.Driver, dsn) {
+				errs = append(errs, fmt.Errorf("YAO_DB_SECONDARY[%d] does not enable TLS, but YAO_DB_REQUIRE_TLS is set (%s)", i, tlsHint(c.DB.Driver)))
+			}
+		}
+	}
+	return errs.Err()
+}
+
+// dbWarnings 数据库配置警告 (sqlite3 配置从库, 数据库位于临时目录)
+func (c Config) dbWarnings() error {
+	warnings := Errors{}
+	if c.DB.Driver == "sqlite3" && len(c.DB.Secondary) > 0 {
+		warnings = append(warnings, fmt.Errorf("YAO_DB_SECONDARY is set but sqlite3 does not support replicas, the secondary connections share the same database file"))
+	}
+
+	for _, path := range c.ephemeralDBPaths() {
+		warnings = append(warnings, fmt.Errorf("The sqlite3 database %s is in a temporary directory and may be lost on restart, use a mounted volume instead", path))
+	}
+	return warnings.Err()
+}
+
+// sqliteJournalModes sqlite3 支持的日志模式
+var sqliteJournalModes = []string{"WAL", "DELETE", "TRUNCATE", "PERSIST", "MEMORY", "OFF"}
+
+// SQLitePragmas sqlite3 连接参数对应的 PRAGMA 语句 (YAO_DB_SQLITE_JOURNAL_MODE, YAO_DB_SQLITE_BUSY_TIMEOUT)
+// 未设置日志模式时不输出 journal_mode, 保持数据库文件当前的日志模式
+func (c Config) SQLitePragmas() []string {
+	pragmas := []string{}
+	if mode := strings.ToUpper(strings.TrimSpace(c.DB.SQLiteJournalMode)); mode != "" {
+		pragmas = append(pragmas, "PRAGMA journal_mode="+mode)
+	}
+	if c.DB.SQLiteBusyTimeout > 0 {
+		pragmas = append(pragmas, fmt.Sprintf("PRAGMA busy_timeout=%d", c.DB.SQLiteBusyTimeout.Milliseconds()))
+	}
+	return pragmas
+}
+
+// SQLiteDSN 为 sqlite3 DSN 添加日志模式及锁定等待时间参数 (_journal_mode, _busy_timeout), DSN 中已设置的参数保持不变
+// 其他数据库驱动返回原 DSN
+func (db DBConfig) SQLiteDSN(dsn string) string {
+	if db.Driver != "sqlite3" {
+		return dsn
+	}
+
+	params := dsnParams(dsn)
+	add := []string{}
+	if mode := strings.ToUpper(strings.TrimSpace(db.SQLiteJournalMode)); mode != "" && params["_journal_mode"] == "" && params["_journal"] == "" {
+		add = append(add, "_journal_mode="+mode)
+	}
+	if db.SQLiteBusyTimeout > 0 && params["_busy_timeout"] == "" && params["_timeout"] == "" {
+		add = append(add, fmt.Sprintf("_busy_timeout=%d", db.SQLiteBusyTimeout.Milliseconds()))
+	}
+	if len(add) == 0 {
+		return dsn
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&" + strings.Join(add, "&")
+	}
+	return dsn + "?" + strings.Join(add, "&")
+}
+
+// dsnTLS DSN 是否启用了 TLS (sqlite3 为本地文件, 不需要 TLS)
+func dsnTLS(driver, dsn string) bool {
+	params := dsnParams(dsn)
+	switch driver {
+	case "postgres":
+		switch params["sslmode"] {
+		case "require", "verify-ca", "verify-full":
+			return true
+		}
+		return false
+	case "mysql":
+		tls := params["tls"]
+		return tls != "" && tls != "false" && tls != "preferred"
+	}
+	return true
+}
+
+// tlsHint 启用 TLS 的 DSN 参数说明
+func tlsHint(driver string) string {
+	switch driver {
+	case "postgres":
+		return "add sslmode=require"
+	case "mysql":
+		return "add tls=true"
+	}
+	return ""
+}
+
+// EnsureDSNTLS 为未启用 TLS 的数据库 DSN 添加 TLS 参数 (postgres: sslmode=require, mysql: tls=true)
+func (c *Config) EnsureDSNTLS() {
+	for i, dsn := range c.DB.Primary {
+		c.DB.Primary[i] = ensureDSNTLS(c.DB.Driver, dsn)
+	}
+	for i, dsn := range c.DB.Secondary {
+		c.DB.Secondary[i] = ensureDSNTLS(c.DB.Driver, dsn)
+	}
+}
+
+func ensureDSNTLS(driver, dsn string) string {
+	if dsnTLS(driver, dsn) {
+		return dsn
+	}
+
+	param := ""
+	switch driver {
+	case "postgres":
+		param = "sslmode=require"
+		if _, has := dsnParams(dsn)["sslmode"]; has {
+			return dsnSetParam(dsn, "sslmode", "require")
+		}
+	case "mysql":
+		param = "tls=true"
+		if _, has := dsnParams(dsn)["tls"]; has {
+			return dsnSetParam(dsn, "tls", "true")
+		}
+	default:
+		return dsn
+	}
+
+	// postgres key=value 格式
+	if driver == "postgres" && !strings.Contains(dsn, "://") {
+		return strings.TrimSpace(dsn) + " " + param
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&" + param
+	}
+	return dsn + "?" + param
+}
+
+// dsnParams 读取 DSN 参数 (支持 URL 查询参数及 postgres key=value 格式)
+func dsnParams(dsn string) map[string]string {
+	params := map[string]string{}
+	query := dsn
+	separator := "&"
+	if i := strings.Index(dsn, "?"); i >= 0 {
+		query = dsn[i+1:]
+	} else if strings.Contains(dsn, "://") || !strings.Contains(dsn, "=") {
+		return params
+	} else {
+		separator = " "
+	}
+
+	for _, pair := range strings.Split(query, separator) {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(kv) == 2 {
+			params[strings.ToLower(kv[0])] = strings.ToLower(kv[1])
+		}
+	}
+	return params
+}
+
+// dsnSetParam 修改 DSN 参数值
+func dsnSetParam(dsn, key, value string) string {
+	re := regexp.MustCompile(`(?i)(^|[?& ])` + regexp.QuoteMeta(key) + `=[^& ]*`)
+	return re.ReplaceAllString(dsn, "${1}"+key+"="+value)
+}
+
+// ephemeralDirs 重启后可能被清空的临时目录
+var ephemeralDirs = []string{"/tmp", "/var/tmp", "/dev/shm", "/run"}
+
+// ephemeralDBPaths 位于临时目录中的 sqlite3 数据库文件
+func (c Config) ephemeralDBPaths() []string {
+	paths := []string{}
+	if c.DB.Driver != "sqlite3" {
+		return paths
+	}
+
+	dirs := append([]string{os.TempDir()}, ephemeralDirs...)
+	for _, dsn := range c.DB.Primary {
+		path := sqlitePath(dsn)
+		if path == "" {
+			continue
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(c.Root, path)
+		}
+		path = resolvePath(path)
+		for _, dir := range dirs {
+			if pathWithin(path, resolvePath(dir)) {
+				paths = append(paths, path)
+				break
+			}
+		}
+	}
+	return paths
+}
+
+// sqlitePath 读取 sqlite3 DSN 中的数据库文件路径 (内存数据库返回空字符串)
+func sqlitePath(dsn string) string {
+	path := strings.TrimSpace(dsn)
+	for _, prefix := range []string{"sqlite3://", "sqlite://", "file:"} {
+		path = strings.TrimPrefix(path, prefix)
+	}
+	if i := strings.Index(path, "?"); i >= 0 {
+		path = path[:i]
+	}
+	if path == "" || path == ":memory:" {
+		return ""
+	}
+	return path
+}
@@ -0,0 +1,27 @@
+package config
+
+// DefaultConfig 默认配置 (仅使用结构体标签中的默认值, 不读取环境变量)
+func DefaultConfig() Config {
+	cfg, _ := parse(map[string]string{})
+	return cfg
+}
+
+// NonDefault 返回与默认配置不同的配置项 (环境变量名称 => 值, 敏感信息已隐藏)
+// 按环境变量格式比较, 列表为 nil 与空列表视为相同
+func (c Config) NonDefault() map[string]string {
+	result := map[string]string{}
+	cfg := c.clone()
+	defaults := DefaultConfig()
+	defaultFields := defaults.fields()
+	for i, field := range cfg.fields() {
+		value := field.text()
+		if value == defaultFields[i].text() {
+			continue
+		}
+		if field.secret() && value != "" {
+			value = redactedValue
+		}
+		result[envVarName(field.Env)] = value
+	}
+	return result
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+// +build !windows
+
+package config
+
+import (
+	"os"
+	"syscall"
+)
+
+// deviceID 读取文件所在设备编号 (无法读取时 ok 为 false)
+func deviceID(path string) (id uint64, ok bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, false
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(stat.Dev), true
+}
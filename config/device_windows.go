@@ -0,0 +1,6 @@
+package config
+
+// deviceID 读取文件所在设备编号 (Windows 不检查, ok 始终为 false)
+func deviceID(path string) (id uint64, ok bool) {
+	return 0, false
+}
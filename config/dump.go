@@ -0,0 +1,53 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/yaoapp/kun/log"
+)
+
+// dumpConfig 将生效配置 (隐藏敏感信息) 写入 YAO_CONFIG_DUMP_PATH, 先写入临时文件再重命名
+// 写入失败仅输出警告, 不影响启动
+func dumpConfig(cfg Config) {
+	if cfg.ConfigDumpPath == "" {
+		return
+	}
+
+	if err := writeConfigDump(cfg.ConfigDumpPath, cfg.Redacted()); err != nil {
+		log.With(log.F{"file": cfg.ConfigDumpPath}).Warn("Can't write config dump. %s", err.Error())
+	}
+}
+
+func writeConfigDump(filename string, cfg Config) error {
+	filename, err := filepath.Abs(filename)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(filename)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/joho/godotenv"
+)
+
+// envBlob 已应用的 YAO_ENV_B64, envBlobKeys 由其写入的环境变量
+var envBlob string
+var envBlobKeys = map[string]bool{}
+var envBlobMutex sync.Mutex
+
+// applyEnvBlob 读取 YAO_ENV_B64 (base64 编码的 .env 文件内容), 解析后写入环境变量
+// 已设置的环境变量优先 (不覆盖); 同一内容只应用一次, 重新加载配置时不会覆盖运行期间修改的环境变量
+func applyEnvBlob() error {
+	name := envVarName("YAO_ENV_B64")
+	blob := strings.TrimSpace(os.Getenv(name))
+	if blob == "" {
+		return nil
+	}
+
+	envBlobMutex.Lock()
+	defer envBlobMutex.Unlock()
+	if blob == envBlob {
+		return nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(blob)
+	if err != nil {
+		return fmt.Errorf("%s is not valid base64: %s", name, err.Error())
+	}
+
+	vars, err := godotenv.Unmarshal(string(data))
+	if err != nil {
+		return fmt.Errorf("%s is not a valid env file: %s", name, err.Error())
+	}
+	for key, value := range vars {
+		if _, has := os.LookupEnv(key); has && !envBlobKeys[key] {
+			continue
+		}
+		os.Setenv(key, value)
+		envBlobKeys[key] = true
+	}
+	envBlob = blob
+	return nil
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// Environ 以环境变量形式 (KEY=VALUE, 使用 SetEnvPrefix 设定的前缀) 输出配置, 用于传递给子进程 (如插件) exec.Cmd.Env
+// includeSecrets 为 false 时, 不输出标记 secret:"true" 的字段; 空列表字段不输出
+func (c Config) Environ(includeSecrets bool) []string {
+	cfg := c.clone()
+	vars := []string{}
+	for _, field := range cfg.fields() {
+		if field.secret() && !includeSecrets {
+			continue
+		}
+		// 空列表不输出 (与未设置等价)
+		if kind := field.Value.Kind(); (kind == reflect.Slice || kind == reflect.Map) && field.Value.Len() == 0 {
+			continue
+		}
+		vars = append(vars, envVarName(field.Env)+"="+field.text())
+	}
+	return vars
+}
+
+// PluginEnviron 输出传递给插件子进程的环境变量 (按 YAO_PLUGIN_ENV_ALLOWLIST 过滤)
+// 未设置 YAO_PLUGIN_ENV_ALLOWLIST 时输出全部非敏感配置; 设置后只输出列出的配置项 (敏感配置项须明确列出) 及系统环境变量
+func (c Config) PluginEnviron() []string {
+	if len(c.PluginEnvAllowlist) == 0 {
+		return c.Environ(false)
+	}
+
+	vars := []string{}
+	for _, pair := range c.Environ(true) {
+		if contains(c.PluginEnvAllowlist, strings.SplitN(pair, "=", 2)[0]) {
+			vars = append(vars, pair)
+		}
+	}
+
+	// 配置项只输出配置值, 不读取同名的系统环境变量
+	fields := map[string]bool{}
+	for _, field := range (&Config{}).fields() {
+		fields[envVarName(field.Env)] = true
+	}
+
+	for _, name := range c.PluginEnvAllowlist {
+		name = strings.TrimSpace(name)
+		if fields[name] {
+			continue
+		}
+		if value, has := os.LookupEnv(name); has {
+			vars = append(vars, name+"="+value)
+		}
+	}
+	return vars
+}
+
+// text 输出字段值 (格式与环境变量解析格式一致, 列表使用配置的分隔符连接)
+func (field configField) text() string {
+	return valueText(field.Value, field.Separator)
+}
+
+func valueText(value reflect.Value, separator string) string {
+	if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return ""
+		}
+		return string(text)
+	}
+
+	if value.Kind() == reflect.Slice {
+		items := []string{}
+		for i := 0; i < value.Len(); i++ {
+			items = append(items, valueText(value.Index(i), separator))
+		}
+		return strings.Join(items, separator)
+	}
+	return fmt.Sprint(value.Interface())
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"net/http"
+	"strings"
+)
+
+// errorFormats 支持的错误响应格式
+var errorFormats = []string{"legacy", "simple", "problem"}
+
+// ErrorResponder 错误响应构造方法 (根据状态码及错误信息返回 Content-Type 及响应内容)
+type ErrorResponder func(status int, message string) (contentType string, body []byte)
+
+// ErrorResponder 根据 YAO_ERROR_FORMAT 返回错误响应构造方法
+//
+//	legacy  {"code": 500, "message": "..."} (默认)
+//	simple  {"error": "..."}
+//	problem RFC 7807 application/problem+json {"type": "about:blank", "title": "...", "status": 500, "detail": "..."}
+func (s ServiceConfig) ErrorResponder() ErrorResponder {
+	switch strings.ToLower(strings.TrimSpace(s.ErrorFormat)) {
+	case "simple":
+		return func(status int, message string) (string, []byte) {
+			return s.errorJSON("application/json; charset=utf-8", map[string]interface{}{"error": message})
+		}
+	case "problem":
+		return func(status int, message string) (string, []byte) {
+			return s.errorJSON("application/problem+json", map[string]interface{}{
+				"type":   "about:blank",
+				"title":  http.StatusText(status),
+				"status": status,
+				"detail": message,
+			})
+		}
+	}
+	return func(status int, message string) (string, []byte) {
+		return s.errorJSON("application/json; charset=utf-8", map[string]interface{}{"code": status, "message": message})
+	}
+}
+
+func (s ServiceConfig) errorJSON(contentType string, body map[string]interface{}) (string, []byte) {
+	data, _ := s.JSONMarshal(body)
+	return contentType, data
+}
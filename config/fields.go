@@ -0,0 +1,103 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"strings"
+)
+
+// configField 配置项信息 (根据 env 标签读取)
+type configField struct {
+	Name      string        // 字段名称 (嵌套字段使用 . 连接, 如 DB.Driver)
+	Env       string        // 环境变量名称
+	Default   string        // 默认值
+	Separator string        // 列表分隔符
+	Value     reflect.Value // 字段值
+	Field     reflect.StructField
+	Struct    string // 字段所属结构体名称 (如 DBConfig)
+}
+
+// fields 读取配置的全部配置项 (按结构体定义顺序)
+func (c *Config) fields() []configField {
+	return structFields(reflect.ValueOf(c).Elem(), "")
+}
+
+func structFields(value reflect.Value, prefix string) []configField {
+	result := []configField{}
+	typ := value.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" { // 未导出字段
+			continue
+		}
+
+		name := field.Name
+		if prefix != "" {
+			name = prefix + "." + field.Name
+		}
+
+		tag := field.Tag.Get("env")
+		if tag == "" {
+			if field.Type.Kind() == reflect.Struct {
+				if field.Anonymous {
+					name = prefix
+				}
+				result = append(result, structFields(value.Field(i), name)...)
+			}
+			continue
+		}
+
+		separator := field.Tag.Get("envSeparator")
+		if separator == "" {
+			separator = ","
+		}
+
+		result = append(result, configField{
+			Name:      name,
+			Env:       strings.Split(tag, ",")[0],
+			Default:   field.Tag.Get("envDefault"),
+			Separator: separator,
+			Value:     value.Field(i),
+			Field:     field,
+			Struct:    typ.Name(),
+		})
+	}
+	return result
+}
+
+// environ 读取当前环境变量 (已设定自定义前缀时转换为 YAO_ 前缀)
+func environ() map[string]string {
+	vars := map[string]string{}
+	for _, pair := range os.Environ() {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) == 2 {
+			vars[kv[0]] = kv[1]
+		}
+	}
+	return applyEnvPrefix(vars)
+}
+
+// clone 复制配置 (列表和字典字段重新分配, 修改副本不影响原配置)
+func (c Config) clone() Config {
+	cp := c
+	for _, field := range cp.fields() {
+		value := field.Value
+		switch value.Kind() {
+		case reflect.Slice:
+			if !value.IsNil() {
+				slice := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+				reflect.Copy(slice, value)
+				value.Set(slice)
+			}
+		case reflect.Map:
+			if !value.IsNil() {
+				m := reflect.MakeMapWithSize(value.Type(), value.Len())
+				for _, key := range value.MapKeys() {
+					m.SetMapIndex(key, value.MapIndex(key))
+				}
+				value.Set(m)
+			}
+		}
+	}
+	return cp
+}
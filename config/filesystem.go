@@ -0,0 +1,27 @@
+package config
+
+import "fmt"
+
+// sameDeviceRoots 须位于同一文件系统的应用目录 (跨目录重命名操作要求同一设备)
+var sameDeviceRoots = []string{"data", "db"}
+
+// filesystemIssues 检查应用根目录及 data, db 目录是否位于同一文件系统 (跨目录的重命名操作会失败: invalid cross-device link)
+// 严格模式 (YAO_STRICT) 下为校验错误, 否则为警告
+func (c Config) filesystemIssues() error {
+	root, ok := deviceID(c.Root)
+	if !ok {
+		return nil
+	}
+
+	errs := Errors{}
+	for _, name := range sameDeviceRoots {
+		dir := c.RootOf(name)
+		dev, ok := deviceID(dir)
+		if !ok || dev == root {
+			continue
+		}
+
+		errs = append(errs, fmt.Errorf("the %s directory %s is on a different filesystem than the application root %s, renaming files across them fails with \"invalid cross-device link\"", name, dir, c.Root))
+	}
+	return errs.Err()
+}
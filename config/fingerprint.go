@@ -0,0 +1,35 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+)
+
+// Fingerprint 配置指纹 (配置内容的 SHA256 摘要)
+func (c Config) Fingerprint() string {
+	data, _ := json.Marshal(c)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Diff 对比配置, 返回值不同的配置项名称
+func (c Config) Diff(other Config) []string {
+	changed := []string{}
+	fields := other.fields()
+	for i, field := range c.fields() {
+		if !reflect.DeepEqual(field.Value.Interface(), fields[i].Value.Interface()) {
+			changed = append(changed, field.Name)
+		}
+	}
+	return changed
+}
+
+// shortFingerprint 指纹前8位 (用于日志输出)
+func shortFingerprint(fingerprint string) string {
+	if len(fingerprint) > 8 {
+		return fingerprint[:8]
+	}
+	return fingerprint
+}
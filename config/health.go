@@ -0,0 +1,137 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HealthChecks 健康检查项列表 (使用 | 分隔, 例: db|session|url:https://dep/health)
+type HealthChecks []string
+
+// healthCheckNames 内置健康检查项名称 (url:<地址> 检查项除外)
+var healthCheckNames = []string{"db", "session"}
+
+// UnmarshalText 解析健康检查项列表 (未知检查项返回错误)
+func (checks *HealthChecks) UnmarshalText(text []byte) error {
+	values := HealthChecks{}
+	for _, value := range strings.Split(string(text), "|") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if strings.HasPrefix(value, "url:") {
+			u, err := url.Parse(strings.TrimPrefix(value, "url:"))
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("invalid health check %q (format: url:http(s)://host/path)", value)
+			}
+		} else if !contains(healthCheckNames, value) {
+			return fmt.Errorf("unknown health check %q (available: %s, url:<address>)", value, strings.Join(healthCheckNames, ", "))
+		}
+		values = append(values, value)
+	}
+	*checks = values
+	return nil
+}
+
+// MarshalText 输出健康检查项列表
+func (checks HealthChecks) MarshalText() ([]byte, error) {
+	return []byte(strings.Join(checks, "|")), nil
+}
+
+var healthCheckers = map[string]func(ctx context.Context) error{}
+var healthCheckersMutex sync.Mutex
+
+// RegisterHealthCheck 注册健康检查项实现 (db, session 由对应的服务注册)
+func RegisterHealthCheck(name string, check func(ctx context.Context) error) {
+	healthCheckersMutex.Lock()
+	defer healthCheckersMutex.Unlock()
+	healthCheckers[name] = check
+}
+
+// HealthCheck 执行 YAO_HEALTH_CHECKS 中的健康检查项, 返回各检查项结果 (nil 为正常)
+// 每个检查项的超时时间为 YAO_CONFIG_FETCH_TIMEOUT
+func (c Config) HealthCheck() map[string]error {
+	timeout := c.ConfigFetchTimeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	results := map[string]error{}
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range c.HealthChecks {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			err := runHealthCheck(ctx, name)
+			if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				err = fmt.Errorf("health check %s timed out after %s (YAO_CONFIG_FETCH_TIMEOUT)", name, timeout)
+			}
+			mutex.Lock()
+			results[name] = err
+			mutex.Unlock()
+		}(name)
+	}
+	wg.Wait()
+	return results
+}
+
+// Healthy 全部健康检查项是否正常
+func (c Config) Healthy() bool {
+	for _, err := range c.HealthCheck() {
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// healthCheckRegistered 是否已注册健康检查项实现
+func healthCheckRegistered(name string) bool {
+	healthCheckersMutex.Lock()
+	defer healthCheckersMutex.Unlock()
+	_, has := healthCheckers[name]
+	return has
+}
+
+// runHealthCheck 执行单个健康检查项
+func runHealthCheck(ctx context.Context, name string) error {
+	if strings.HasPrefix(name, "url:") {
+		return checkURL(ctx, strings.TrimPrefix(name, "url:"))
+	}
+
+	healthCheckersMutex.Lock()
+	check, has := healthCheckers[name]
+	healthCheckersMutex.Unlock()
+	if !has {
+		return fmt.Errorf("health check %s is not available", name)
+	}
+	return check(ctx)
+}
+
+// checkURL 请求下游服务地址 (返回 2xx 状态码为正常)
+func checkURL(ctx context.Context, address string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("health check %s failed: %s", address, res.Status)
+	}
+	return nil
+}
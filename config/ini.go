@@ -0,0 +1,128 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LoadINI 从 INI 格式配置文件加载配置
+// 先应用 [default] 分区, 再叠加与运行模式 (YAO_ENV) 同名的分区, 如 [production] [development]
+// 配置项名称为去掉 YAO_ / XIANG_ 前缀的环境变量名称 (例: PORT=5099, SESSION_PORT=3322), 也可使用完整名称
+func LoadINI(path string) (Config, error) {
+	sections, err := readINI(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	names := iniNames()
+	vars := map[string]string{}
+	for key, value := range sections["default"] {
+		name, err := iniEnvName(names, path, "default", key)
+		if err != nil {
+			return Config{}, err
+		}
+		vars[name] = value
+	}
+
+	mode, has := vars["YAO_ENV"]
+	if !has {
+		mode = environ()["YAO_ENV"]
+	}
+	if mode == "" {
+		mode = "production"
+	}
+
+	for key, value := range sections[mode] {
+		name, err := iniEnvName(names, path, mode, key)
+		if err != nil {
+			return Config{}, err
+		}
+		vars[name] = value
+	}
+
+	for name, value := range vars {
+		os.Setenv(envVarName(name), value)
+	}
+
+	markLoaded()
+	return parseValidEnv()
+}
+
+// readINI 读取 INI 文件 (分区名称 => 配置项), 分区之前的配置项归入 default 分区
+func readINI(path string) (map[string]map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sections := map[string]map[string]string{"default": {}}
+	section := "default"
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") {
+			if !strings.HasSuffix(text, "]") {
+				return nil, fmt.Errorf("%s:%d: invalid section %s", path, line, text)
+			}
+			section = strings.ToLower(strings.TrimSpace(text[1 : len(text)-1]))
+			if _, has := sections[section]; !has {
+				sections[section] = map[string]string{}
+			}
+			continue
+		}
+
+		kv := strings.SplitN(text, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected key=value, got %s", path, line, text)
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %s", path, line, err.Error())
+			}
+			value = unquoted
+		} else if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			value = value[1 : len(value)-1]
+		}
+		sections[section][strings.ToUpper(key)] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return sections, nil
+}
+
+// iniNames INI 配置项名称 => 环境变量名称
+func iniNames() map[string]string {
+	names := map[string]string{}
+	cfg := Config{}
+	for _, field := range cfg.fields() {
+		names[field.Env] = field.Env
+		for _, prefix := range []string{"YAO_", "XIANG_"} {
+			if strings.HasPrefix(field.Env, prefix) {
+				names[strings.TrimPrefix(field.Env, prefix)] = field.Env
+			}
+		}
+	}
+	return names
+}
+
+// iniEnvName 读取 INI 配置项对应的环境变量名称 (未知配置项返回错误)
+func iniEnvName(names map[string]string, path, section, key string) (string, error) {
+	name, has := names[key]
+	if !has {
+		return "", fmt.Errorf("%s [%s]: unknown config %s", path, section, key)
+	}
+	return name, nil
+}
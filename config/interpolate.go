@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/yaoapp/kun/log"
+)
+
+// 配置值中可以引用环境变量 (${VAR} 或 $VAR, 加载配置时替换, 未设置的变量替换为空字符串并输出警告, $$ 表示 $ 本身)
+// 以下内置变量优先于同名的环境变量:
+//
+//	${HOSTNAME} 主机名称
+//	${PID}      进程 ID
+//	${NOW}      进程首次加载配置的时间 (RFC3339, 同一进程内保持不变)
+//
+// 例: YAO_LOG=${YAO_ROOT}/logs/yao-${HOSTNAME}-${PID}.log
+//
+// 标记 secret:"true" 的配置项 (如 YAO_JWT_SECRET, YAO_DB_PRIMARY) 不替换, 密码中的 $ 原样保留
+var tokenRe = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+var loadTime string
+var loadTimeOnce sync.Once
+
+// builtinTokens 内置变量
+func builtinTokens() map[string]string {
+	loadTimeOnce.Do(func() {
+		loadTime = currentClock().Now().Format(time.RFC3339)
+	})
+	hostname, _ := os.Hostname()
+	return map[string]string{
+		"HOSTNAME": hostname,
+		"PID":      strconv.Itoa(os.Getpid()),
+		"NOW":      loadTime,
+	}
+}
+
+// interpolate 替换字符串配置项 (包括字符串列表) 中的内置变量及引用的环境变量 (vars 为加载配置使用的环境变量, 敏感配置项除外)
+func (c *Config) interpolate(vars map[string]string) {
+	tokens := builtinTokens()
+	expand := func(name, value string) string {
+		return tokenRe.ReplaceAllStringFunc(value, func(token string) string {
+			if token == "$$" {
+				return "$"
+			}
+			match := tokenRe.FindStringSubmatch(token)
+			ref := match[1] + match[2]
+			if value, has := tokens[ref]; has {
+				return value
+			}
+			if value, has := vars[ref]; has {
+				return value
+			}
+			log.Warn("%s references the undefined variable %s, it is replaced with an empty string", name, ref)
+			return ""
+		})
+	}
+
+	for _, field := range c.fields() {
+		if field.secret() {
+			continue
+		}
+		value := field.Value
+		name := envVarName(field.Env)
+		switch {
+		case value.Kind() == reflect.String:
+			value.SetString(expand(name, value.String()))
+		case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.String:
+			for i := 0; i < value.Len(); i++ {
+				value.Index(i).SetString(expand(name, value.Index(i).String()))
+			}
+		}
+	}
+}
+
+// placeholderRe 未替换的变量或模板 (${VAR}, {{ .Field }})
+var placeholderRe = regexp.MustCompile(`\$\{[^}]*\}|\{\{[^}]*\}\}`)
+
+// placeholderIssues 字符串配置项中残留的未替换变量或模板 (如通过 Update 设置的值)
+// 严格模式 (YAO_STRICT) 下为校验错误, 否则为警告
+func (c Config) placeholderIssues() error {
+	errs := Errors{}
+	check := func(name, value string) {
+		for _, token := range placeholderRe.FindAllString(value, -1) {
+			errs = append(errs, fmt.Errorf("%s contains the unexpanded placeholder %s, check that the referenced variable is set", name, token))
+		}
+	}
+
+	for _, field := range c.fields() {
+		value := field.Value
+		switch {
+		case value.Kind() == reflect.String:
+			check(envVarName(field.Env), value.String())
+		case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.String:
+			for i := 0; i < value.Len(); i++ {
+				check(fmt.Sprintf("%s[%d]", envVarName(field.Env), i), value.Index(i).String())
+			}
+		}
+	}
+	return errs.Err()
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yaoapp/kun/log"
+)
+
+// journalSocket systemd journal 原生协议套接字
+const journalSocket = "/run/systemd/journal/socket"
+
+// journalPriorities 日志级别与 syslog 优先级对照
+var journalPriorities = map[string]int{
+	"panic":   0,
+	"fatal":   2,
+	"error":   3,
+	"warning": 4,
+	"warn":    4,
+	"info":    6,
+	"debug":   7,
+	"trace":   7,
+}
+
+var journalLevelRe = regexp.MustCompile(`"?level"?\s*[:=]\s*"?([a-zA-Z]+)`)
+
+// journalWriter 日志写入 systemd journal
+type journalWriter struct {
+	conn       net.Conn
+	identifier string
+}
+
+// openJournalLog 日志输出到 systemd journal (identifier 为 journal://[标识名称] 中的标识名称)
+func openJournalLog(identifier string, cfg Config) error {
+	journal, err := newJournalWriter(identifier)
+	if err != nil {
+		return fmt.Errorf("systemd journal is not available. %s", err.Error())
+	}
+
+	logSink = journal
+	log.SetOutput(newLogWriter(journal, cfg))
+	gin.DefaultWriter = journal
+	return nil
+}
+
+func newJournalWriter(identifier string) (*journalWriter, error) {
+	if _, err := os.Stat(journalSocket); err != nil {
+		return nil, err
+	}
+	conn, err := net.Dial("unixgram", journalSocket)
+	if err != nil {
+		return nil, err
+	}
+	return &journalWriter{conn: conn, identifier: identifier}, nil
+}
+
+// Write 每行日志作为一条 journal 记录写入
+func (w *journalWriter) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		msg := &bytes.Buffer{}
+		journalField(msg, "PRIORITY", strconv.Itoa(journalPriority(line)))
+		journalField(msg, "SYSLOG_IDENTIFIER", w.identifier)
+		journalField(msg, "MESSAGE", string(line))
+		if _, err := w.conn.Write(msg.Bytes()); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Close 关闭 journal 连接
+func (w *journalWriter) Close() error {
+	return w.conn.Close()
+}
+
+// journalPriority 根据日志级别返回 syslog 优先级 (默认 info)
+func journalPriority(line []byte) int {
+	match := journalLevelRe.FindSubmatch(line)
+	if match == nil {
+		return 6
+	}
+	if priority, has := journalPriorities[strings.ToLower(string(match[1]))]; has {
+		return priority
+	}
+	return 6
+}
+
+// journalField 按 journal 原生协议写入字段 (包含换行的值使用二进制格式)
+func journalField(buf *bytes.Buffer, key, value string) {
+	if !strings.Contains(value, "\n") {
+		buf.WriteString(key + "=" + value + "\n")
+		return
+	}
+	buf.WriteString(key + "\n")
+	binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value + "\n")
+}
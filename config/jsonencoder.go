@@ -0,0 +1,30 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/kun/log"
+)
+
+// jsonEncoders 支持的 JSON 编码库
+var jsonEncoders = []string{"stdlib", "jsoniter"}
+
+// jsonEncoderWarned 已输出警告的未知编码库
+var jsonEncoderWarned sync.Map
+
+// JSONMarshal 使用 YAO_JSON_ENCODER 指定的编码库编码 JSON (未知编码库输出警告并使用标准库)
+func (s ServiceConfig) JSONMarshal(v interface{}) ([]byte, error) {
+	switch strings.ToLower(strings.TrimSpace(s.JSONEncoder)) {
+	case "", "stdlib":
+	case "jsoniter":
+		return jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(v)
+	default:
+		if _, warned := jsonEncoderWarned.LoadOrStore(s.JSONEncoder, true); !warned {
+			log.Warn("YAO_JSON_ENCODER must be one of %s (got %q), use stdlib", strings.Join(jsonEncoders, ", "), s.JSONEncoder)
+		}
+	}
+	return json.Marshal(v)
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// minJWTSecretBytes JWT 密钥最小长度 (字节)
+const minJWTSecretBytes = 32
+
+// weakJWTSecrets 常见的弱密钥及示例密钥 (含 yao init 生成的 .env 中的示例值)
+var weakJWTSecrets = []string{
+	"secret", "changeme", "change-me", "password", "jwt", "jwt-secret", "jwtsecret",
+	"yao", "xiang", "123456", "12345678", "default", "test", "example",
+	"bLp@bi!oqo-2U+hoTRUG",
+}
+
+// jwtSecretIssues 检查 JWT 密钥强度 (生产环境或已设置密钥时检查: 密钥为空, 短于 32 字节, 或为常见弱密钥)
+// 严格模式 (YAO_STRICT) 下为校验错误, 否则为警告
+func (c Config) jwtSecretIssues() error {
+	if c.JWTSecret == "" && c.Mode != "production" {
+		return nil
+	}
+
+	switch {
+	case c.JWTSecret == "":
+		return fmt.Errorf("YAO_JWT_SECRET is not set, tokens can be forged, use a random value of at least %d bytes", minJWTSecretBytes)
+	case weakJWTSecret(c.JWTSecret):
+		return fmt.Errorf("YAO_JWT_SECRET is a well-known placeholder value, use a random value of at least %d bytes", minJWTSecretBytes)
+	case len(c.JWTSecret) < minJWTSecretBytes:
+		return fmt.Errorf("YAO_JWT_SECRET is %d bytes, shorter secrets make token forgery easier, use a random value of at least %d bytes", len(c.JWTSecret), minJWTSecretBytes)
+	}
+	return nil
+}
+
+// weakJWTSecret 是否为常见弱密钥 (忽略大小写及首尾空白)
+func weakJWTSecret(secret string) bool {
+	secret = strings.TrimSpace(secret)
+	for _, weak := range weakJWTSecrets {
+		if strings.EqualFold(secret, weak) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// KillSwitches 限时关闭的功能 (功能名称 => 到期时间, 格式 feature_a@2024-01-02T15:04:05Z,feature_b@...)
+type KillSwitches map[string]time.Time
+
+// UnmarshalText 解析限时关闭的功能
+func (switches *KillSwitches) UnmarshalText(text []byte) error {
+	values := KillSwitches{}
+	for _, pair := range strings.Split(string(text), ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "@", 2)
+		name := strings.TrimSpace(kv[0])
+		if len(kv) != 2 || name == "" {
+			return fmt.Errorf("invalid kill switch %q, the format should be name@RFC3339 time", pair)
+		}
+		expiry, err := time.Parse(time.RFC3339, strings.TrimSpace(kv[1]))
+		if err != nil {
+			return fmt.Errorf("invalid kill switch %q expiry: %s", pair, err.Error())
+		}
+		values[name] = expiry
+	}
+	*switches = values
+	return nil
+}
+
+// MarshalText 输出限时关闭的功能
+func (switches KillSwitches) MarshalText() ([]byte, error) {
+	names := []string{}
+	for name := range switches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := []string{}
+	for _, name := range names {
+		pairs = append(pairs, name+"@"+switches[name].Format(time.RFC3339))
+	}
+	return []byte(strings.Join(pairs, ",")), nil
+}
+
+// KillSwitchActive 功能是否处于关闭状态 (到期后自动恢复)
+func (c Config) KillSwitchActive(name string) bool {
+	expiry, has := c.KillSwitches[name]
+	return has && currentClock().Now().Before(expiry)
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ValidateLogConsistency 检查日志格式与日志地址等配置项的组合是否有效
+func (c Config) ValidateLogConsistency() error {
+	errs := Errors{}
+
+	mode := strings.ToUpper(strings.TrimSpace(c.LogMode))
+	if mode != "" && mode != "TEXT" && mode != "JSON" {
+		errs = append(errs, fmt.Errorf("YAO_LOG_MODE must be TEXT or JSON (got %q)", c.LogMode))
+	}
+
+	redact := strings.ToLower(strings.TrimSpace(c.LogFieldRedact))
+	if redact != "" && redact != "strip" && redact != "hash" {
+		errs = append(errs, fmt.Errorf("YAO_LOG_FIELD_REDACT must be strip or hash (got %q)", c.LogFieldRedact))
+	}
+
+	for _, from := range c.LogFieldMap.keys() {
+		if strings.TrimSpace(c.LogFieldMap[from]) == "" {
+			errs = append(errs, fmt.Errorf("YAO_LOG_FIELD_MAP: field %q must be mapped to a non-empty name (format: from=to)", from))
+		}
+	}
+
+	for i, dest := range c.logDestinations() {
+		name := "YAO_LOG"
+		if strings.TrimSpace(c.Log) == "" || i > 0 {
+			name = "YAO_LOG_FALLBACKS"
+		}
+		d, err := ParseLogDestination(dest)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s", name, err.Error()))
+			continue
+		}
+
+		// systemd journal 按行读取日志级别并转换为优先级, 使用 TEXT 格式
+		if d.Kind == LogDestJournal && mode == "JSON" {
+			errs = append(errs, fmt.Errorf("%s %s is a systemd journal destination, which requires YAO_LOG_MODE=TEXT (got JSON)", name, dest))
+		}
+	}
+
+	errs = append(errs, c.logTimeIssues()...)
+
+	seen := map[string]bool{}
+	for _, dest := range c.logDestinations() {
+		if seen[dest] {
+			errs = append(errs, fmt.Errorf("log destination %s is listed more than once in YAO_LOG and YAO_LOG_FALLBACKS", dest))
+		}
+		seen[dest] = true
+	}
+	return errs.Err()
+}
+
+// logTimeIssues 检查日志时间格式 (YAO_LOG_TIME_FORMAT) 与 YAO_LOG_TIME_UTC, YAO_LOG_EXPECTED_TZ 是否一致
+func (c Config) logTimeIssues() Errors {
+	errs := Errors{}
+	layout := c.LogTimeFormat
+	if layout != "" {
+		// 时钟读数相同, 时区不同的两个时间: 格式化结果相同时说明时间格式不含时区
+		east := time.Date(2001, 2, 3, 4, 5, 6, 0, time.FixedZone("CST", 8*3600))
+		west := time.Date(2001, 2, 3, 4, 5, 6, 0, time.FixedZone("EST", -5*3600))
+		if east.Format(layout) == layout {
+			errs = append(errs, fmt.Errorf("YAO_LOG_TIME_FORMAT %q contains no time fields, use a Go time layout such as 2006-01-02T15:04:05Z07:00", layout))
+		} else if !c.LogTimeUTC && east.Format(layout) == west.Format(layout) {
+			// 时间格式不含时区, 且日志时间未转换为 UTC, 不同时区主机的日志时间无法比较
+			errs = append(errs, fmt.Errorf("YAO_LOG_TIME_FORMAT %q has no timezone, add a zone (e.g. Z07:00) or set YAO_LOG_TIME_UTC=true", layout))
+		}
+	}
+
+	expected := strings.ToLower(strings.TrimSpace(c.LogExpectedTZ))
+	if c.LogTimeUTC && expected != "" && expected != "utc" {
+		errs = append(errs, fmt.Errorf("YAO_LOG_TIME_UTC converts log timestamps to UTC, but YAO_LOG_EXPECTED_TZ is %s", c.LogExpectedTZ))
+	}
+	return errs
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// 日志地址类型
+const (
+	LogDestFile    = "file"    // 日志文件 (路径或 file://路径)
+	LogDestJournal = "journal" // systemd journal (journal://[标识名称])
+	LogDestStdout  = "stdout"  // 标准输出 (stdout 或 stdout://)
+	LogDestStderr  = "stderr"  // 标准错误输出 (stderr 或 stderr://)
+)
+
+// logDestKinds 支持的日志地址类型
+var logDestKinds = []string{LogDestFile, LogDestJournal, LogDestStdout, LogDestStderr}
+
+// LogDestination 日志地址
+type LogDestination struct {
+	Kind    string            // 类型 file|journal|stdout|stderr
+	Path    string            // 文件路径或 journal 标识名称
+	Options map[string]string // 地址参数 (例: journal://yao?key=value)
+}
+
+// ParseLogDestination 解析并校验日志地址 (YAO_LOG 及 YAO_LOG_FALLBACKS 统一使用此方法解析)
+// 支持: /path/to/yao.log, file:///path/to/yao.log, journal://[标识名称], stdout, stderr
+func ParseLogDestination(s string) (LogDestination, error) {
+	dest := strings.TrimSpace(s)
+	if dest == "" {
+		return LogDestination{}, fmt.Errorf("log destination is empty")
+	}
+
+	switch strings.ToLower(dest) {
+	case LogDestStdout, LogDestStderr:
+		return LogDestination{Kind: strings.ToLower(dest), Options: map[string]string{}}, nil
+	}
+
+	kv := strings.SplitN(dest, "://", 2)
+	if len(kv) != 2 {
+		return LogDestination{Kind: LogDestFile, Path: dest, Options: map[string]string{}}, nil
+	}
+
+	scheme, rest := strings.ToLower(kv[0]), kv[1]
+	options := map[string]string{}
+	if i := strings.Index(rest, "?"); i >= 0 {
+		query, err := url.ParseQuery(rest[i+1:])
+		if err != nil {
+			return LogDestination{}, fmt.Errorf("invalid log destination %s: %s", dest, err.Error())
+		}
+		for key := range query {
+			options[key] = query.Get(key)
+		}
+		rest = rest[:i]
+	}
+
+	switch scheme {
+	case LogDestFile:
+		if rest == "" {
+			return LogDestination{}, fmt.Errorf("invalid log destination %s: the file path is empty", dest)
+		}
+		return LogDestination{Kind: LogDestFile, Path: rest, Options: options}, nil
+
+	case LogDestJournal:
+		identifier := strings.Trim(rest, "/")
+		if identifier == "" {
+			identifier = "yao"
+		}
+		return LogDestination{Kind: LogDestJournal, Path: identifier, Options: options}, nil
+
+	case LogDestStdout, LogDestStderr:
+		return LogDestination{Kind: scheme, Options: options}, nil
+	}
+	return LogDestination{}, fmt.Errorf("unsupported log destination scheme %q in %s (supported: %s)", scheme, dest, strings.Join(logDestKinds, ", "))
+}
+
+// String 输出日志地址
+func (d LogDestination) String() string {
+	switch d.Kind {
+	case LogDestFile:
+		return d.Path
+	case LogDestJournal:
+		return "journal://" + d.Path
+	}
+	return d.Kind
+}
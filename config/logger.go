@@ -0,0 +1,353 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yaoapp/kun/log"
+)
+
+// Formatter 自定义日志格式 (接收日志条目字段, 返回输出内容)
+type Formatter interface {
+	Format(entry map[string]interface{}) ([]byte, error)
+}
+
+// logFormatter 自定义日志格式 (由 SetFormatter 设定)
+var logFormatter Formatter
+var logFormatterMutex sync.RWMutex
+
+// logWriter 日志输出 (写入前按配置处理日志字段)
+type logWriter struct {
+	out       io.Writer
+	policy    logPolicy
+	formatter Formatter
+}
+
+// logPolicy 日志字段处理策略
+type logPolicy struct {
+	deny   map[string]bool
+	hash   bool
+	fields LogFields // 每条日志附加的字段
+	rename LogFields // 字段重命名 (原名称 => 新名称)
+	layout string    // 日志时间格式 (为空时保持原格式)
+	utc    bool      // 日志时间是否转换为 UTC
+}
+
+// LogFields 日志附加字段 (格式 key=value,key2=value2)
+type LogFields map[string]string
+
+// UnmarshalText 解析日志附加字段 (忽略无法解析的字段)
+func (fields *LogFields) UnmarshalText(text []byte) error {
+	values := LogFields{}
+	for _, pair := range strings.Split(string(text), ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			log.Warn("Ignore log field %q, the format should be key=value", pair)
+			continue
+		}
+		values[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	*fields = values
+	return nil
+}
+
+// MarshalText 输出日志附加字段
+func (fields LogFields) MarshalText() ([]byte, error) {
+	pairs := []string{}
+	for _, key := range fields.keys() {
+		pairs = append(pairs, key+"="+fields[key])
+	}
+	return []byte(strings.Join(pairs, ",")), nil
+}
+
+// keys 字段名称 (按名称排序)
+func (fields LogFields) keys() []string {
+	keys := []string{}
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SetFormatter 设定自定义日志格式 (高级用法, 供嵌入 Yao 的应用使用)
+// 设定后切换运行模式不会覆盖自定义格式, 调用 SetFormatter(nil) 恢复内建格式 TEXT|JSON
+func SetFormatter(f Formatter) {
+	logFormatterMutex.Lock()
+	logFormatter = f
+	logFormatterMutex.Unlock()
+	setLogFormat()
+	ReloadLog()
+}
+
+// setLogFormat 设定日志格式 (使用自定义格式时, 日志按 JSON 格式输出交由自定义格式处理)
+func setLogFormat() {
+	logFormatterMutex.RLock()
+	custom := logFormatter != nil
+	logFormatterMutex.RUnlock()
+
+	log.SetFormatter(log.TEXT)
+	if custom || Get().LogMode == "JSON" {
+		log.SetFormatter(log.JSON)
+	}
+}
+
+// newLogWriter 创建日志输出 (未设定字段处理策略及自定义格式时直接写入)
+func newLogWriter(out io.Writer, cfg Config) io.Writer {
+	logFormatterMutex.RLock()
+	formatter := logFormatter
+	logFormatterMutex.RUnlock()
+
+	policy := cfg.logPolicy()
+	if policy.empty() && formatter == nil {
+		return out
+	}
+	return &logWriter{out: out, policy: policy, formatter: formatter}
+}
+
+// logFiltered 日志输出是否需要处理
+func (c Config) logFiltered() bool {
+	logFormatterMutex.RLock()
+	defer logFormatterMutex.RUnlock()
+	return logFormatter != nil || !c.logPolicy().empty()
+}
+
+// Write 写入日志 (JSON 格式逐行解析, TEXT 格式按 key=value 处理)
+func (w *logWriter) Write(p []byte) (int, error) {
+	lines := bytes.SplitAfter(p, []byte("\n"))
+	out := make([]byte, 0, len(p))
+	for _, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+		out = append(out, w.line(line)...)
+	}
+
+	if _, err := w.out.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// logPolicy 读取日志字段处理策略
+func (c Config) logPolicy() logPolicy {
+	policy := logPolicy{
+		deny:   map[string]bool{},
+		hash:   strings.ToLower(c.LogFieldRedact) == "hash",
+		fields: c.LogDefaultFields,
+		rename: c.LogFieldMap,
+		layout: c.LogTimeFormat,
+		utc:    c.LogTimeUTC,
+	}
+
+	// 附加程序版本信息 (不覆盖 YAO_LOG_FIELDS 中的同名字段)
+	if info := c.VersionInfo(); len(info) > 0 {
+		fields := LogFields{}
+		for key, value := range info {
+			fields[key] = value
+		}
+		for key, value := range c.LogDefaultFields {
+			fields[key] = value
+		}
+		policy.fields = fields
+	}
+	for _, field := range c.LogFieldDenylist {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field != "" {
+			policy.deny[field] = true
+		}
+	}
+	return policy
+}
+
+// RedactLogField 按日志字段策略处理字段值, 返回处理后的值及是否被处理 (hash 模式返回摘要, 否则返回空字符串)
+func (c Config) RedactLogField(key, value string) (string, bool) {
+	return c.logPolicy().redact(key, value)
+}
+
+func (policy logPolicy) empty() bool {
+	return len(policy.deny) == 0 && len(policy.fields) == 0 && len(policy.rename) == 0 && policy.layout == "" && !policy.utc
+}
+
+// formatTime 按 YAO_LOG_TIME_FORMAT 及 YAO_LOG_TIME_UTC 转换日志时间 (RFC3339), 返回转换后的时间及是否转换
+func (policy logPolicy) formatTime(value string) (string, bool) {
+	if policy.layout == "" && !policy.utc {
+		return value, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return value, false
+	}
+	if policy.utc {
+		t = t.UTC()
+	}
+	layout := policy.layout
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	return t.Format(layout), true
+}
+
+// timeText 转换 TEXT 格式日志的 time 字段
+func (policy logPolicy) timeText(line []byte) []byte {
+	if policy.layout == "" && !policy.utc {
+		return line
+	}
+	return textFieldRe.ReplaceAllFunc(line, func(match []byte) []byte {
+		parts := textFieldRe.FindSubmatch(match)
+		if string(parts[2]) != "time" {
+			return match
+		}
+		value, ok := policy.formatTime(strings.Trim(string(parts[3]), `"`))
+		if !ok {
+			return match
+		}
+		if strings.ContainsAny(value, " \"=") {
+			value = strconv.Quote(value)
+		}
+		return []byte(string(parts[1]) + "time=" + value)
+	})
+}
+
+func (policy logPolicy) redact(key, value string) (string, bool) {
+	if !policy.deny[strings.ToLower(key)] {
+		return value, false
+	}
+	if policy.hash {
+		sum := sha256.Sum256([]byte(value))
+		return "sha256:" + hex.EncodeToString(sum[:])[:16], true
+	}
+	return "", true
+}
+
+// line 处理单行日志
+func (w *logWriter) line(line []byte) []byte {
+	trimmed := bytes.TrimSpace(line)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		entry := map[string]interface{}{}
+		if err := json.Unmarshal(trimmed, &entry); err == nil {
+			w.policy.applyFields(entry)
+			if value, ok := entry["time"].(string); ok {
+				entry["time"], _ = w.policy.formatTime(value)
+			}
+			for key, value := range w.policy.fields {
+				if _, has := entry[key]; !has {
+					entry[key] = value
+				}
+			}
+			w.policy.renameFields(entry)
+			if w.formatter != nil {
+				if data, err := w.formatter.Format(entry); err == nil {
+					return data
+				}
+			}
+			if data, err := json.Marshal(entry); err == nil {
+				return append(data, '\n')
+			}
+		}
+	}
+	return w.policy.renameText(w.policy.appendText(w.policy.timeText(w.policy.applyText(line))))
+}
+
+// renameFields 按 YAO_LOG_FIELD_MAP 重命名 JSON 日志字段 (仅处理顶层字段)
+func (policy logPolicy) renameFields(entry map[string]interface{}) {
+	renamed := map[string]interface{}{}
+	for from, to := range policy.rename {
+		if value, has := entry[from]; has && to != "" {
+			renamed[to] = value
+			delete(entry, from)
+		}
+	}
+	for key, value := range renamed {
+		entry[key] = value
+	}
+}
+
+// renameText 按 YAO_LOG_FIELD_MAP 重命名 TEXT 格式日志 key=value 字段
+func (policy logPolicy) renameText(line []byte) []byte {
+	if len(policy.rename) == 0 {
+		return line
+	}
+	return textFieldRe.ReplaceAllFunc(line, func(match []byte) []byte {
+		parts := textFieldRe.FindSubmatch(match)
+		to := policy.rename[string(parts[2])]
+		if to == "" {
+			return match
+		}
+		return []byte(string(parts[1]) + to + "=" + string(parts[3]))
+	})
+}
+
+// applyFields 处理 JSON 日志字段 (包括嵌套字段)
+func (policy logPolicy) applyFields(fields map[string]interface{}) {
+	for key, value := range fields {
+		if policy.deny[strings.ToLower(key)] {
+			if !policy.hash {
+				delete(fields, key)
+				continue
+			}
+			text, ok := value.(string)
+			if !ok {
+				data, _ := json.Marshal(value)
+				text = string(data)
+			}
+			fields[key], _ = policy.redact(key, text)
+			continue
+		}
+
+		if nested, ok := value.(map[string]interface{}); ok {
+			policy.applyFields(nested)
+		}
+	}
+}
+
+var textFieldRe = regexp.MustCompile(`(^|\s)([A-Za-z0-9_.\-]+)=("(?:[^"\\]|\\.)*"|\S*)`)
+
+// applyText 处理 TEXT 格式日志 key=value 字段
+func (policy logPolicy) applyText(line []byte) []byte {
+	return textFieldRe.ReplaceAllFunc(line, func(match []byte) []byte {
+		parts := textFieldRe.FindSubmatch(match)
+		key := string(parts[2])
+		if !policy.deny[strings.ToLower(key)] {
+			return match
+		}
+		if !policy.hash {
+			return parts[1][:0]
+		}
+		value, _ := policy.redact(key, strings.Trim(string(parts[3]), `"`))
+		return []byte(string(parts[1]) + key + "=" + value)
+	})
+}
+
+// appendText 在 TEXT 格式日志末尾附加字段
+func (policy logPolicy) appendText(line []byte) []byte {
+	if len(policy.fields) == 0 {
+		return line
+	}
+
+	newline := bytes.HasSuffix(line, []byte("\n"))
+	text := string(bytes.TrimRight(line, "\n"))
+	for _, key := range policy.fields.keys() {
+		if strings.Contains(" "+text, " "+key+"=") {
+			continue
+		}
+		text += " " + key + "=" + strconv.Quote(policy.fields[key])
+	}
+	if newline {
+		text += "\n"
+	}
+	return []byte(text)
+}
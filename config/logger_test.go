@@ -0,0 +1,394 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogWriterDenylist(t *testing.T) {
+	cfg := Config{LogFieldDenylist: []string{"email", "Phone"}}
+	buf := &bytes.Buffer{}
+	w := newLogWriter(buf, cfg)
+
+	w.Write([]byte(`{"level":"info","msg":"signup","email":"a@b.c","user":{"phone":"123","id":1}}` + "\n"))
+	assert.NotContains(t, buf.String(), "a@b.c")
+	assert.NotContains(t, buf.String(), "123")
+	assert.Contains(t, buf.String(), `"id":1`)
+
+	buf.Reset()
+	w.Write([]byte(`time="now" level=info msg="signup" email="a@b.c" id=1` + "\n"))
+	assert.Equal(t, `time="now" level=info msg="signup" id=1`+"\n", buf.String())
+
+	buf.Reset()
+	cfg.LogFieldRedact = "hash"
+	newLogWriter(buf, cfg).Write([]byte("level=info email=a@b.c\n"))
+	assert.True(t, strings.HasPrefix(buf.String(), "level=info email=sha256:"))
+
+	value, redacted := cfg.RedactLogField("EMAIL", "a@b.c")
+	assert.True(t, redacted)
+	assert.NotEqual(t, "a@b.c", value)
+
+	value, redacted = cfg.RedactLogField("name", "yao")
+	assert.False(t, redacted)
+	assert.Equal(t, "yao", value)
+
+	assert.Equal(t, buf, newLogWriter(buf, Config{}))
+}
+
+type testFormatter struct{}
+
+func (testFormatter) Format(entry map[string]interface{}) ([]byte, error) {
+	return []byte(fmt.Sprintf("[%v] %v\n", entry["level"], entry["msg"])), nil
+}
+
+func TestLogWriterFormatter(t *testing.T) {
+	SetFormatter(testFormatter{})
+	defer SetFormatter(nil)
+
+	buf := &bytes.Buffer{}
+	newLogWriter(buf, Config{}).Write([]byte(`{"level":"info","msg":"hello"}` + "\n"))
+	assert.Equal(t, "[info] hello\n", buf.String())
+}
+
+func TestLogDefaultFields(t *testing.T) {
+	var fields LogFields
+	assert.Nil(t, fields.UnmarshalText([]byte("dc=us1, cluster=prod-a,broken")))
+	assert.Equal(t, LogFields{"dc": "us1", "cluster": "prod-a"}, fields)
+
+	buf := &bytes.Buffer{}
+	w := newLogWriter(buf, Config{LogDefaultFields: fields})
+	w.Write([]byte(`{"level":"info","msg":"hello","dc":"eu1"}` + "\n"))
+	assert.Contains(t, buf.String(), `"cluster":"prod-a"`)
+	assert.Contains(t, buf.String(), `"dc":"eu1"`)
+
+	buf.Reset()
+	w.Write([]byte("level=info msg=hello\n"))
+	assert.Equal(t, "level=info msg=hello cluster=\"prod-a\" dc=\"us1\"\n", buf.String())
+}
+
+func TestJournalPriority(t *testing.T) {
+	assert.Equal(t, 3, journalPriority([]byte(`{"level":"error","msg":"failed"}`)))
+	assert.Equal(t, 4, journalPriority([]byte(`time="now" level=warning msg="slow"`)))
+	assert.Equal(t, 6, journalPriority([]byte(`hello`)))
+
+	buf := &bytes.Buffer{}
+	journalField(buf, "MESSAGE", "a\nb")
+	assert.Equal(t, "MESSAGE\n\x03\x00\x00\x00\x00\x00\x00\x00a\nb\n", buf.String())
+}
+
+func TestLogFallbacks(t *testing.T) {
+	prev := Conf
+	defer func() {
+		CloseLog()
+		Conf = prev
+		ReloadLog()
+	}()
+
+	fallback := filepath.Join(t.TempDir(), "yao.log")
+	Conf.Log = "/proc/yao/unavailable/yao.log"
+	Conf.LogFallbacks = []string{"", fallback}
+	assert.Equal(t, []string{"/proc/yao/unavailable/yao.log", fallback}, Conf.logDestinations())
+
+	ReloadLog()
+	assert.Equal(t, fallback, LogOutput.Name())
+}
+
+func TestReloadLogKeepsHandle(t *testing.T) {
+	prev := Conf
+	defer func() {
+		CloseLog()
+		Conf = prev
+		ReloadLog()
+	}()
+
+	dir := t.TempDir()
+	Conf.Log = filepath.Join(dir, "yao.log")
+	ReloadLog()
+	output := LogOutput
+
+	Conf.LogFieldDenylist = []string{"password"}
+	ReloadLog()
+	assert.True(t, output == LogOutput)
+
+	Conf.Log = filepath.Join(dir, "yao2.log")
+	ReloadLog()
+	assert.False(t, output == LogOutput)
+	assert.Equal(t, Conf.Log, LogOutput.Name())
+}
+
+func TestReopenLog(t *testing.T) {
+	prev := Conf
+	defer func() {
+		CloseLog()
+		Conf = prev
+		ReloadLog()
+	}()
+
+	dir := t.TempDir()
+	Conf.Log = filepath.Join(dir, "yao.log")
+	ReloadLog()
+
+	// 模拟 logrotate: 重命名日志文件后重新打开
+	assert.Nil(t, os.Rename(Conf.Log, Conf.Log+".1"))
+	ReopenLog()
+	_, err := os.Stat(Conf.Log)
+	assert.Nil(t, err)
+	assert.Equal(t, Conf.Log, LogOutput.Name())
+
+	// 信号处理 (ReopenLog) 与重新加载配置 (ReloadLog) 同时修改日志输出
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				ReopenLog()
+				return
+			}
+			ReloadLog()
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, Conf.Log, LogOutput.Name())
+
+	Conf.Log = ""
+	ReopenLog()
+	assert.Equal(t, "", logOpenedDest)
+}
+
+func TestReloadLogRotation(t *testing.T) {
+	prev := Conf
+	defer func() {
+		CloseLog()
+		Conf = prev
+		ReloadLog()
+	}()
+
+	Conf.Log = filepath.Join(t.TempDir(), "yao.log")
+	Conf.LogMaxSize, Conf.LogMaxBackups = 0, 0
+	ReloadLog()
+	assert.Nil(t, logRotate)
+
+	// 日志地址不变时按新的轮转配置包装已打开的日志文件
+	Conf.LogMaxSize, Conf.LogMaxBackups = 10, 3
+	ReloadLog()
+	if assert.NotNil(t, logRotate) {
+		assert.Equal(t, int64(10*1024*1024), logRotate.maxSize)
+		assert.Equal(t, 3, logRotate.maxBackups)
+	}
+
+	CloseLog()
+	assert.Nil(t, logRotate)
+	ReopenLog()
+	assert.NotNil(t, logRotate)
+}
+
+func TestRotateWriter(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	SetClock(fakeClock{now: now})
+	defer SetClock(nil)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "yao.log")
+	old := filepath.Join(dir, "yao-"+now.AddDate(0, 0, -10).Format(rotateTimeFormat)+".log")
+	assert.Nil(t, os.WriteFile(old, []byte("old\n"), 0644))
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := newRotateWriter(file, Config{LogMaxSize: 1, LogMaxAge: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// 超过保留天数的轮转文件在打开时清理
+	_, err = os.Stat(old)
+	assert.True(t, os.IsNotExist(err))
+
+	w.maxSize = 10
+	w.Write([]byte("12345678\n"))
+	w.Write([]byte("abcdefgh\n"))
+	assert.Len(t, w.backups(), 1)
+
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "abcdefgh\n", string(data))
+	data, _ = os.ReadFile(w.backups()[0].path)
+	assert.Equal(t, "12345678\n", string(data))
+
+	// 超出保留数量的轮转文件在轮转时清理
+	w.maxBackups = 1
+	SetClock(fakeClock{now: now.Add(time.Second)})
+	w.Write([]byte("ABCDEFGH\n"))
+	backups := w.backups()
+	assert.Len(t, backups, 1)
+	data, _ = os.ReadFile(backups[0].path)
+	assert.Equal(t, "abcdefgh\n", string(data))
+
+	assert.Nil(t, w.Close())
+	_, err = w.Write([]byte("closed\n"))
+	assert.Equal(t, os.ErrClosed, err)
+}
+
+func TestPipeWriter(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, isNamedPipe(w))
+
+	pipe := newPipeWriter(w, false)
+	n, err := pipe.Write([]byte("level=info msg=hello\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, 21, n)
+
+	// 读取端关闭后写入 stderr
+	r.Close()
+	n, err = pipe.Write([]byte("level=info msg=closed\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, 22, n)
+	assert.Nil(t, pipe.file)
+	assert.Nil(t, pipe.Close())
+}
+
+func TestStdWriter(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	std := newStdWriter(w)
+	_, err = std.Write([]byte("level=info msg=hello\n"))
+	assert.Nil(t, err)
+
+	// 读取端关闭后写入 stderr
+	r.Close()
+	n, err := std.Write([]byte("level=info msg=closed\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, 22, n)
+	assert.True(t, std.broken)
+}
+
+func TestLogTimeFormat(t *testing.T) {
+	cfg := Config{LogTimeFormat: "2006-01-02 15:04:05Z07:00", LogTimeUTC: true}
+	buf := &bytes.Buffer{}
+	w := newLogWriter(buf, cfg)
+	w.Write([]byte(`{"level":"info","msg":"hello","time":"2022-03-04T08:05:06+08:00"}` + "\n"))
+	assert.Contains(t, buf.String(), `"time":"2022-03-04 00:05:06Z"`)
+
+	buf.Reset()
+	w.Write([]byte(`time="2022-03-04T08:05:06+08:00" level=info msg=hello` + "\n"))
+	assert.Equal(t, `time="2022-03-04 00:05:06Z" level=info msg=hello`+"\n", buf.String())
+
+	buf.Reset()
+	w.Write([]byte(`time="now" level=info` + "\n"))
+	assert.Equal(t, `time="now" level=info`+"\n", buf.String())
+
+	assert.Nil(t, cfg.ValidateLogConsistency())
+	assert.Nil(t, Config{LogTimeFormat: "2006-01-02T15:04:05.000Z07:00"}.ValidateLogConsistency())
+	assert.Contains(t, Config{LogTimeFormat: "2006-01-02 15:04:05"}.ValidateLogConsistency().Error(), "has no timezone")
+	assert.Nil(t, Config{LogTimeFormat: "2006-01-02 15:04:05", LogTimeUTC: true}.ValidateLogConsistency())
+	assert.Contains(t, Config{LogTimeFormat: "iso"}.ValidateLogConsistency().Error(), "contains no time fields")
+	assert.Contains(t, Config{LogTimeUTC: true, LogExpectedTZ: "local"}.ValidateLogConsistency().Error(), "YAO_LOG_EXPECTED_TZ is local")
+}
+
+func TestValidateLogConsistency(t *testing.T) {
+	tests := []struct {
+		mode      string
+		log       string
+		fallbacks []string
+		redact    string
+		err       string
+	}{
+		{mode: "TEXT", log: "/var/log/yao.log"},
+		{mode: "JSON", log: "/var/log/yao.log"},
+		{mode: "TEXT", log: "journal://yao"},
+		{mode: "json", log: ""},
+		{mode: "TEXT", log: "journal://yao", redact: "hash"},
+		{mode: "JSON", log: "journal://yao", err: "YAO_LOG journal://yao is a systemd journal destination"},
+		{mode: "JSON", log: "/var/log/yao.log", fallbacks: []string{"journal://"}, err: "YAO_LOG_FALLBACKS journal://"},
+		{mode: "XML", log: "/var/log/yao.log", err: "YAO_LOG_MODE must be TEXT or JSON"},
+		{mode: "TEXT", redact: "mask", err: "YAO_LOG_FIELD_REDACT must be strip or hash"},
+		{mode: "TEXT", log: "/var/log/yao.log", fallbacks: []string{"/var/log/yao.log"}, err: "listed more than once"},
+	}
+
+	for _, test := range tests {
+		cfg := Config{LogMode: test.mode, Log: test.log, LogFallbacks: test.fallbacks, LogFieldRedact: test.redact}
+		err := cfg.ValidateLogConsistency()
+		if test.err == "" {
+			assert.Nil(t, err, test)
+			continue
+		}
+		if assert.NotNil(t, err, test) {
+			assert.Contains(t, err.Error(), test.err)
+		}
+	}
+}
+
+func TestLogFieldMap(t *testing.T) {
+	var mapping LogFields
+	assert.Nil(t, mapping.UnmarshalText([]byte("level=severity,msg=message")))
+	cfg := Config{LogFieldMap: mapping}
+
+	buf := &bytes.Buffer{}
+	w := newLogWriter(buf, cfg)
+	w.Write([]byte(`{"level":"info","msg":"hello","user":1}` + "\n"))
+	assert.Equal(t, `{"message":"hello","severity":"info","user":1}`+"\n", buf.String())
+
+	buf.Reset()
+	w.Write([]byte(`time="now" level=info msg="hello" user=1` + "\n"))
+	assert.Equal(t, `time="now" severity=info message="hello" user=1`+"\n", buf.String())
+
+	cfg.LogFieldMap = LogFields{"level": ""}
+	assert.Contains(t, cfg.ValidateLogConsistency().Error(), `YAO_LOG_FIELD_MAP: field "level" must be mapped to a non-empty name`)
+}
+
+func TestParseLogDestination(t *testing.T) {
+	tests := []struct {
+		dest    string
+		kind    string
+		path    string
+		options map[string]string
+		err     string
+	}{
+		{dest: "/var/log/yao.log", kind: LogDestFile, path: "/var/log/yao.log"},
+		{dest: "logs/yao.log", kind: LogDestFile, path: "logs/yao.log"},
+		{dest: "file:///var/log/yao.log", kind: LogDestFile, path: "/var/log/yao.log"},
+		{dest: "journal://", kind: LogDestJournal, path: "yao"},
+		{dest: "journal://myapp?facility=local0", kind: LogDestJournal, path: "myapp", options: map[string]string{"facility": "local0"}},
+		{dest: "stdout", kind: LogDestStdout},
+		{dest: "STDERR", kind: LogDestStderr},
+		{dest: "stderr://", kind: LogDestStderr},
+		{dest: "", err: "log destination is empty"},
+		{dest: "file://", err: "the file path is empty"},
+		{dest: "syslog://localhost", err: "unsupported log destination scheme \"syslog\" in syslog://localhost (supported: file, journal, stdout, stderr)"},
+	}
+
+	for _, test := range tests {
+		d, err := ParseLogDestination(test.dest)
+		if test.err != "" {
+			assert.Contains(t, fmt.Sprint(err), test.err, test.dest)
+			continue
+		}
+		assert.Nil(t, err, test.dest)
+		assert.Equal(t, test.kind, d.Kind, test.dest)
+		assert.Equal(t, test.path, d.Path, test.dest)
+		if test.options == nil {
+			test.options = map[string]string{}
+		}
+		assert.Equal(t, test.options, d.Options, test.dest)
+	}
+
+	cfg := Config{Log: "syslog://localhost"}
+	assert.Contains(t, cfg.ValidateLogConsistency().Error(), "YAO_LOG: unsupported log destination scheme")
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/yaoapp/kun/log"
+)
+
+// 运行时切换的维护模式 (独立于配置, 重新加载配置后保持)
+const (
+	maintenanceUnset int32 = iota // 未切换, 使用配置 YAO_MAINTENANCE
+	maintenanceOn
+	maintenanceOff
+)
+
+var maintenance int32 = maintenanceUnset
+
+// InMaintenance 是否处于维护模式 (SetMaintenance 切换后以运行时状态为准, 否则使用 YAO_MAINTENANCE)
+func (c Config) InMaintenance() bool {
+	switch atomic.LoadInt32(&maintenance) {
+	case maintenanceOn:
+		return true
+	case maintenanceOff:
+		return false
+	}
+	return c.Maintenance
+}
+
+// SetMaintenance 切换维护模式 (立即生效, 并通知配置变更)
+// 运行时状态不写入配置, 重新加载配置 (Reload 等) 后保持, 进程重启后恢复为 YAO_MAINTENANCE
+func SetMaintenance(on bool) {
+	writeMutex.Lock()
+	cfg := Get()
+	if cfg.InMaintenance() == on {
+		writeMutex.Unlock()
+		return
+	}
+
+	state := maintenanceOff
+	if on {
+		state = maintenanceOn
+	}
+	atomic.StoreInt32(&maintenance, state)
+	writeMutex.Unlock()
+
+	log.With(log.F{"maintenance": on}).Info("maintenance mode changed")
+	AuditEvent("system", "maintenance", fmt.Sprintf("maintenance: %v", on))
+	fireReload(cfg)
+}
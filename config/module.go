@@ -0,0 +1,257 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ModuleDependencies 子系统模块依赖关系 (模块名称 => 依赖的模块)
+var ModuleDependencies = map[string][]string{
+	"script":   {},
+	"model":    {},
+	"flow":     {},
+	"plugin":   {},
+	"table":    {"model"},
+	"chart":    {"flow"},
+	"page":     {"flow"},
+	"importer": {"model"},
+	"workflow": {"model"},
+	"api":      {},
+}
+
+var moduleDependenciesMutex sync.RWMutex
+
+// RegisterDependency 声明模块依赖 (module 在 dependsOn 之后启动, 未知模块自动添加)
+func RegisterDependency(module string, dependsOn ...string) {
+	moduleDependenciesMutex.Lock()
+	defer moduleDependenciesMutex.Unlock()
+	for _, name := range append([]string{module}, dependsOn...) {
+		if _, has := ModuleDependencies[name]; !has {
+			ModuleDependencies[name] = []string{}
+		}
+	}
+	for _, dependency := range dependsOn {
+		if !contains(ModuleDependencies[module], dependency) {
+			ModuleDependencies[module] = append(ModuleDependencies[module], dependency)
+		}
+	}
+}
+
+// StartupOrder 已启用模块的启动顺序 (依赖的模块在前, 无依赖关系的模块按名称排序), 存在循环依赖时返回错误
+func (c Config) StartupOrder() ([]string, error) {
+	deps := moduleDependencies()
+	pending := map[string]int{}         // 模块 => 未启动的依赖数量
+	dependents := map[string][]string{} // 模块 => 依赖该模块的模块
+	for _, name := range sortedModules(deps) {
+		if !c.ModuleEnabled(name) {
+			continue
+		}
+		pending[name] = 0
+		for _, dependency := range deps[name] {
+			if !c.ModuleEnabled(dependency) {
+				continue
+			}
+			pending[name]++
+			dependents[dependency] = append(dependents[dependency], name)
+		}
+	}
+
+	order := []string{}
+	ready := []string{}
+	for name, count := range pending {
+		if count == 0 {
+			ready = append(ready, name)
+		}
+	}
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		name := ready[0]
+		ready = ready[1:]
+		order = append(order, name)
+		for _, dependent := range dependents[name] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if len(order) < len(pending) {
+		return nil, fmt.Errorf("module dependency cycle: %s", strings.Join(dependencyCycle(pending, deps), " -> "))
+	}
+	return order, nil
+}
+
+// dependencyCycle 从未能启动的模块中找出一条循环依赖路径 (未能启动的模块均有未启动的依赖)
+func dependencyCycle(pending map[string]int, deps map[string][]string) []string {
+	names := []string{}
+	for name, count := range pending {
+		if count > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	path := []string{}
+	visited := map[string]int{} // 模块 => 在路径中的位置
+	name := names[0]
+	for {
+		if i, has := visited[name]; has {
+			return append(path[i:], name)
+		}
+		visited[name] = len(path)
+		path = append(path, name)
+		for _, dependency := range deps[name] {
+			if pending[dependency] > 0 {
+				name = dependency
+				break
+			}
+		}
+	}
+}
+
+// ModuleEnabled 子系统模块是否启用
+func (c Config) ModuleEnabled(name string) bool {
+	for _, disabled := range c.DisableModules {
+		if strings.TrimSpace(disabled) == name {
+			return false
+		}
+	}
+
+	if len(c.Modules) == 0 {
+		return true
+	}
+
+	for _, enabled := range c.Modules {
+		if strings.TrimSpace(enabled) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validateModules 检查模块名称是否有效, 已启用模块的依赖是否被禁用, 以及是否存在循环依赖
+func (c Config) validateModules() error {
+	deps := moduleDependencies()
+	errs := Errors{}
+	for _, names := range [][]string{c.Modules, c.DisableModules} {
+		for _, name := range names {
+			name = strings.TrimSpace(name)
+			if _, has := deps[name]; !has && name != "" {
+				errs = append(errs, fmt.Errorf("unknown module %q", name))
+			}
+		}
+	}
+
+	for _, name := range sortedModules(deps) {
+		if !c.ModuleEnabled(name) {
+			continue
+		}
+		for _, dependency := range deps[name] {
+			if !c.ModuleEnabled(dependency) {
+				errs = append(errs, fmt.Errorf("module %q is disabled but required by %q", dependency, name))
+			}
+		}
+	}
+
+	if _, err := c.StartupOrder(); err != nil {
+		errs = append(errs, err)
+	}
+	return errs.Err()
+}
+
+// moduleDependencies 返回模块依赖关系的副本 (加读锁读取, RegisterDependency 可能同时修改)
+func moduleDependencies() map[string][]string {
+	moduleDependenciesMutex.RLock()
+	defer moduleDependenciesMutex.RUnlock()
+	deps := map[string][]string{}
+	for name, dependsOn := range ModuleDependencies {
+		deps[name] = append([]string{}, dependsOn...)
+	}
+	return deps
+}
+
+// moduleNames 已知模块名称 (按名称排序)
+func moduleNames() []string {
+	return sortedModules(moduleDependencies())
+}
+
+// sortedModules 按名称排序的模块名称
+func sortedModules(deps map[string][]string) []string {
+	names := []string{}
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateModuleRoots 检查已启用模块的应用目录是否存在且可读 (不创建目录)
+// 目录不存在时: YAO_MODULE_CREATE_DIRS 中的模块忽略 (启动时由 CreateModuleDirs 创建); YAO_MODULES 中指定启用的模块返回错误; 其他模块忽略
+func (c Config) validateModuleRoots() error {
+	errs := Errors{}
+	for _, name := range moduleNames() {
+		if !c.ModuleEnabled(name) {
+			continue
+		}
+
+		dir := c.RootOf(name)
+		if dir == "" {
+			continue
+		}
+
+		info, err := os.Stat(dir)
+		if os.IsNotExist(err) {
+			if !contains(c.ModuleCreateDirs, name) && contains(c.Modules, name) {
+				errs = append(errs, fmt.Errorf("module %q is enabled but its directory %s does not exist", name, dir))
+			}
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("module %q: %s", name, err.Error()))
+			continue
+		}
+		if !info.IsDir() {
+			errs = append(errs, fmt.Errorf("module %q: %s is not a directory", name, dir))
+			continue
+		}
+		if f, err := os.Open(dir); err != nil {
+			errs = append(errs, fmt.Errorf("module %q: directory %s is not readable: %s", name, dir, err.Error()))
+		} else {
+			f.Close()
+		}
+	}
+	return errs.Err()
+}
+
+// CreateModuleDirs 为 YAO_MODULE_CREATE_DIRS 中已启用的模块创建不存在的应用目录 (启动时调用)
+func (c Config) CreateModuleDirs() error {
+	errs := Errors{}
+	for _, name := range moduleNames() {
+		if !c.ModuleEnabled(name) || !contains(c.ModuleCreateDirs, name) {
+			continue
+		}
+
+		dir := c.RootOf(name)
+		if dir == "" {
+			continue
+		}
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			errs = append(errs, fmt.Errorf("module %q: can't create directory %s: %s", name, dir, err.Error()))
+		}
+	}
+	return errs.Err()
+}
+
+// contains 列表中是否包含指定名称 (忽略首尾空白)
+func contains(names []string, name string) bool {
+	for _, item := range names {
+		if strings.TrimSpace(item) == name {
+			return true
+		}
+	}
+	return false
+}
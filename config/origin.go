@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Origin 跨域访问来源
+type Origin struct {
+	Scheme   string // 协议 http|https (为空不限)
+	Host     string // 域名 (通配时为上级域名, 例: *.example.com 为 example.com; 为空匹配全部)
+	Port     int    // 端口 (0 不限)
+	Wildcard bool   // 是否匹配子域名
+}
+
+// ParsedAllow 解析跨域访问域名列表 (YAO_ALLOW)
+func (s ServiceConfig) ParsedAllow() ([]Origin, error) {
+	origins := []Origin{}
+	for i, value := range s.Allow {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		origin, err := parseOrigin(value)
+		if err != nil {
+			return nil, fmt.Errorf("YAO_ALLOW[%d] %q: %s", i, value, err)
+		}
+		origins = append(origins, origin)
+	}
+	return origins, nil
+}
+
+// AllowOrigin 请求来源是否在跨域访问域名列表中 (列表为空时不允许跨域访问)
+func (s ServiceConfig) AllowOrigin(origin string) bool {
+	origins, err := s.ParsedAllow()
+	if err != nil {
+		return false
+	}
+
+	u, err := url.Parse(strings.TrimSpace(origin))
+	if err != nil || u.Host == "" {
+		return false
+	}
+	port, _ := strconv.Atoi(u.Port())
+	request := Origin{Scheme: strings.ToLower(u.Scheme), Host: strings.ToLower(u.Hostname()), Port: port}
+	for _, allow := range origins {
+		if allow.Match(request) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match 来源是否匹配
+func (o Origin) Match(request Origin) bool {
+	if o.Scheme != "" && o.Scheme != request.Scheme {
+		return false
+	}
+	if o.Port != 0 && o.Port != request.Port {
+		return false
+	}
+	if o.Host == "" {
+		return true
+	}
+	if o.Wildcard {
+		return strings.HasSuffix(request.Host, "."+o.Host)
+	}
+	return o.Host == request.Host
+}
+
+// String 输出来源
+func (o Origin) String() string {
+	host := o.Host
+	if o.Wildcard || host == "" {
+		host = strings.TrimSuffix("*."+host, ".")
+	}
+	if o.Port != 0 {
+		host = net.JoinHostPort(host, strconv.Itoa(o.Port))
+	}
+	if o.Scheme != "" {
+		return o.Scheme + "://" + host
+	}
+	return host
+}
+
+// parseOrigin 解析来源 (格式: [scheme://]host[:port], host 支持 * 及 *.example.com)
+func parseOrigin(value string) (Origin, error) {
+	origin := Origin{}
+	rest := strings.ToLower(value)
+	if i := strings.Index(rest, "://"); i >= 0 {
+		origin.Scheme = rest[:i]
+		rest = rest[i+3:]
+		if origin.Scheme != "http" && origin.Scheme != "https" {
+			return origin, fmt.Errorf("unsupported scheme %q", origin.Scheme)
+		}
+	}
+	if strings.ContainsAny(rest, "/?#@") {
+		return origin, fmt.Errorf("origin must not contain a path, query or user info")
+	}
+
+	host := rest
+	if i := strings.LastIndex(rest, ":"); i >= 0 && !strings.HasSuffix(rest, "]") {
+		port, err := strconv.Atoi(rest[i+1:])
+		if err != nil || port < 1 || port > 65535 {
+			return origin, fmt.Errorf("invalid port %q", rest[i+1:])
+		}
+		origin.Port = port
+		host = rest[:i]
+	}
+
+	switch {
+	case host == "*":
+		origin.Wildcard = true
+		return origin, nil
+	case strings.HasPrefix(host, "*."):
+		origin.Wildcard = true
+		host = host[2:]
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if host == "" || strings.Contains(host, "*") {
+		return origin, fmt.Errorf("invalid host %q", host)
+	}
+	origin.Host = host
+	return origin, nil
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// pipeReconnectInterval 日志管道断开后重新打开的间隔
+var pipeReconnectInterval = 5 * time.Second
+
+// pipeWriter 命名管道日志输出 (读取端关闭时切换到 stderr, 开启 YAO_LOG_PIPE_RECONNECT 时定时重新打开管道)
+type pipeWriter struct {
+	mutex     sync.Mutex
+	file      *os.File // 当前管道 (断开后为 nil)
+	path      string
+	reconnect bool
+	retryAt   time.Time
+}
+
+// isNamedPipe 日志文件是否为命名管道
+func isNamedPipe(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeNamedPipe != 0
+}
+
+// newPipeWriter 创建命名管道日志输出
+func newPipeWriter(file *os.File, reconnect bool) *pipeWriter {
+	return &pipeWriter{file: file, path: file.Name(), reconnect: reconnect}
+}
+
+// Write 写入日志 (管道断开 EPIPE 时输出一次提示并改为写入 stderr)
+func (w *pipeWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.file == nil && w.reconnect && !time.Now().Before(w.retryAt) {
+		w.reopen()
+	}
+
+	if w.file != nil {
+		n, err := w.file.Write(p)
+		if err == nil || !errors.Is(err, syscall.EPIPE) {
+			return n, err
+		}
+		fmt.Fprintf(os.Stderr, "log pipe %s is closed, log to stderr instead\n", w.path)
+		w.file.Close()
+		w.file = nil
+		w.retryAt = time.Now().Add(pipeReconnectInterval)
+	}
+	return os.Stderr.Write(p)
+}
+
+// reopen 重新打开管道 (读取端未就绪时等待下次重试)
+func (w *pipeWriter) reopen() {
+	w.retryAt = time.Now().Add(pipeReconnectInterval)
+	file, err := os.OpenFile(w.path, os.O_WRONLY|os.O_APPEND|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return
+	}
+	// O_NONBLOCK 仅用于读取端未就绪时立即返回, 打开后恢复阻塞写入 (否则管道写满时 EAGAIN 会丢失日志)
+	if err := setBlocking(file); err != nil {
+		file.Close()
+		return
+	}
+	w.file = file
+	fmt.Fprintf(os.Stderr, "log pipe %s is reopened\n", w.path)
+}
+
+// Close 关闭管道
+func (w *pipeWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// stdWriter 日志地址为 stdout 或 stderr 时的输出
+// 读取端关闭 (EPIPE, 如 yao start | head) 时输出一次提示, stdout 改为写入 stderr, stderr 丢弃日志
+type stdWriter struct {
+	mutex  sync.Mutex
+	file   *os.File
+	broken bool
+}
+
+// newStdWriter 创建 stdout 或 stderr 日志输出 (忽略 SIGPIPE, 以便写入时返回 EPIPE 而不是退出进程)
+func newStdWriter(file *os.File) *stdWriter {
+	ignoreBrokenPipe()
+	return &stdWriter{file: file}
+}
+
+// Write 写入日志
+func (w *stdWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if !w.broken {
+		n, err := w.file.Write(p)
+		if err == nil || !errors.Is(err, syscall.EPIPE) {
+			return n, err
+		}
+		w.broken = true
+		if w.file != os.Stderr {
+			fmt.Fprintf(os.Stderr, "log output %s is closed, log to stderr instead\n", w.file.Name())
+		}
+	}
+
+	if w.file == os.Stderr {
+		return len(p), nil
+	}
+	return os.Stderr.Write(p)
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+// +build !windows
+
+package config
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// setBlocking 将文件切换为阻塞模式
+func setBlocking(file *os.File) error {
+	return syscall.SetNonblock(int(file.Fd()), false)
+}
+
+// ignoreBrokenPipe 忽略 SIGPIPE (未处理时写入已关闭的 stdout 或 stderr 会退出进程)
+func ignoreBrokenPipe() {
+	signal.Ignore(syscall.SIGPIPE)
+}
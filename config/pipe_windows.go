@@ -0,0 +1,11 @@
+package config
+
+import "os"
+
+// setBlocking 将文件切换为阻塞模式 (Windows 不使用命名管道日志, 不做处理)
+func setBlocking(file *os.File) error {
+	return nil
+}
+
+// ignoreBrokenPipe 忽略 SIGPIPE (Windows 无此信号, 不做处理)
+func ignoreBrokenPipe() {}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/yaoapp/kun/log"
+)
+
+// reloadDebounce 配置文件修改后等待的时间 (连续写入完成后再重新加载)
+const reloadDebounce = 500 * time.Millisecond
+
+// envModTime 已加载配置文件的修改时间 (定时检查与文件监听共用, 同一修改只重新加载一次)
+var envModTime time.Time
+var envModTimeMutex sync.Mutex
+
+// reloadChangedMutex 保证同一时间只有一个检查在执行
+var reloadChangedMutex sync.Mutex
+
+// recordEnvModTime 记录配置文件的修改时间
+func recordEnvModTime(file string) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return
+	}
+	envModTimeMutex.Lock()
+	envModTime = info.ModTime()
+	envModTimeMutex.Unlock()
+}
+
+// reloadIfChanged 配置文件修改时间变更, 且距最后一次修改超过 reloadDebounce 时重新加载配置, 返回是否执行了重新加载
+// 重新加载失败时同样记录修改时间, 文件再次修改前不会重试
+func reloadIfChanged() (bool, error) {
+	reloadChangedMutex.Lock()
+	defer reloadChangedMutex.Unlock()
+
+	file := envFile
+	if file == "" {
+		return false, nil
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return false, err
+	}
+
+	envModTimeMutex.Lock()
+	last := envModTime
+	envModTimeMutex.Unlock()
+	if info.ModTime().Equal(last) || currentClock().Now().Sub(info.ModTime()) < reloadDebounce {
+		return false, nil
+	}
+
+	err = Reload()
+	envModTimeMutex.Lock()
+	envModTime = info.ModTime()
+	envModTimeMutex.Unlock()
+	return true, err
+}
+
+// PollReload 按 YAO_RELOAD_INTERVAL 定时检查配置文件修改时间, 文件变更时重新加载配置, ctx 取消后退出
+// 用于文件监听事件不可靠的网络文件系统 (NFS, overlayfs); 未设置 YAO_RELOAD_INTERVAL 时直接返回
+func PollReload(ctx context.Context) {
+	interval := Current().ReloadInterval
+	if interval <= 0 {
+		return
+	}
+
+	ticker := currentClock().NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			if _, err := reloadIfChanged(); err != nil {
+				log.Error("config reload failed: %s", err.Error())
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// defaultEnvPrefix 环境变量默认前缀
+const defaultEnvPrefix = "YAO_"
+
+var envPrefix = defaultEnvPrefix
+var envPrefixMutex sync.RWMutex
+
+// loaded 应用是否已加载配置 (包初始化时的加载不计入)
+var loaded bool
+
+// SetEnvPrefix 设定环境变量前缀 (如 MYAPP_, 读取配置时使用该前缀替换 YAO_), 须在 Load 之前调用
+// 设定后不再读取 YAO_ 开头的环境变量
+func SetEnvPrefix(prefix string) error {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return fmt.Errorf("env prefix must not be empty")
+	}
+
+	envPrefixMutex.Lock()
+	defer envPrefixMutex.Unlock()
+	if loaded {
+		return fmt.Errorf("SetEnvPrefix must be called before the config is loaded")
+	}
+	envPrefix = prefix
+	return nil
+}
+
+// currentEnvPrefix 当前环境变量前缀
+func currentEnvPrefix() string {
+	envPrefixMutex.RLock()
+	defer envPrefixMutex.RUnlock()
+	return envPrefix
+}
+
+// markLoaded 记录应用已加载配置 (之后不能再修改环境变量前缀)
+func markLoaded() {
+	envPrefixMutex.Lock()
+	loaded = true
+	envPrefixMutex.Unlock()
+}
+
+// envVarName 配置项对应的环境变量名称 (使用当前前缀)
+func envVarName(name string) string {
+	prefix := currentEnvPrefix()
+	if prefix == defaultEnvPrefix || !strings.HasPrefix(name, defaultEnvPrefix) {
+		return name
+	}
+	return prefix + strings.TrimPrefix(name, defaultEnvPrefix)
+}
+
+// applyEnvPrefix 将自定义前缀的环境变量转换为 YAO_ 前缀 (忽略原有 YAO_ 开头的环境变量)
+func applyEnvPrefix(vars map[string]string) map[string]string {
+	prefix := currentEnvPrefix()
+	if prefix == defaultEnvPrefix {
+		return vars
+	}
+
+	result := map[string]string{}
+	for key, value := range vars {
+		if strings.HasPrefix(key, prefix) {
+			result[defaultEnvPrefix+strings.TrimPrefix(key, prefix)] = value
+		} else if !strings.HasPrefix(key, defaultEnvPrefix) {
+			result[key] = value
+		}
+	}
+	return result
+}
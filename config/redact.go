@@ -0,0 +1,71 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// redactedValue 敏感信息替换值
+const redactedValue = "***"
+
+// Redacted 返回隐藏敏感信息后的配置副本
+// 标记 secret:"true" 的字段及 YAO_REDACT_FIELDS 中列出的字段, 非空值替换为 *** (列表字段替换每一项)
+func (c Config) Redacted() Config {
+	cfg := c.clone()
+	for _, field := range cfg.fields() {
+		if !field.secret() && !contains(c.RedactFields, field.Env) {
+			continue
+		}
+		redact(field.Value)
+	}
+	return cfg
+}
+
+// String 返回隐藏敏感信息后的配置 (JSON 格式), 可直接输出到日志
+// 使用 fmt 输出配置 (%v, %s) 时同样隐藏敏感信息
+func (c Config) String() string {
+	data, err := json.Marshal(c.Redacted())
+	if err != nil {
+		return fmt.Sprintf("config: %s", err.Error())
+	}
+	return string(data)
+}
+
+// redact 替换字段值 (支持字符串及字符串列表)
+func redact(value reflect.Value) {
+	switch value.Kind() {
+	case reflect.String:
+		if value.String() != "" {
+			value.SetString(redactedValue)
+		}
+	case reflect.Slice:
+		if value.Type().Elem().Kind() != reflect.String {
+			return
+		}
+		for i := 0; i < value.Len(); i++ {
+			redact(value.Index(i))
+		}
+	}
+}
+
+// secret 是否为敏感信息字段
+func (field configField) secret() bool {
+	return field.Field.Tag.Get("secret") == "true"
+}
+
+// validateRedactFields 检查 YAO_REDACT_FIELDS 中的环境变量名称是否有效
+func (c Config) validateRedactFields() error {
+	errs := Errors{}
+	envs := []string{}
+	for _, field := range c.fields() {
+		envs = append(envs, field.Env)
+	}
+	for _, name := range c.RedactFields {
+		if !contains(envs, strings.TrimSpace(name)) {
+			errs = append(errs, fmt.Errorf("YAO_REDACT_FIELDS: unknown config %q", name))
+		}
+	}
+	return errs.Err()
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/joho/godotenv"
+	"github.com/yaoapp/kun/log"
+)
+
+var reloadHandlers = []func(Config){}
+var reloadHandlersMutex sync.Mutex
+
+// OnReload 注册配置变更处理器 (配置替换后按注册顺序调用)
+func OnReload(handler func(cfg Config)) {
+	reloadHandlersMutex.Lock()
+	defer reloadHandlersMutex.Unlock()
+	reloadHandlers = append(reloadHandlers, handler)
+}
+
+// saveReloadHandlers 保存当前的配置变更处理器, 返回恢复函数 (移除之后注册的处理器, 用于测试)
+func saveReloadHandlers() func() {
+	reloadHandlersMutex.Lock()
+	saved := make([]func(Config), len(reloadHandlers))
+	copy(saved, reloadHandlers)
+	reloadHandlersMutex.Unlock()
+	return func() {
+		reloadHandlersMutex.Lock()
+		reloadHandlers = saved
+		reloadHandlersMutex.Unlock()
+	}
+}
+
+// fireReload 通知配置已变更
+func fireReload(cfg Config) {
+	publish(cfg)
+	reloadHandlersMutex.Lock()
+	handlers := make([]func(Config), len(reloadHandlers))
+	copy(handlers, reloadHandlers)
+	reloadHandlersMutex.Unlock()
+
+	for _, handler := range handlers {
+		handler(cfg)
+	}
+}
+
+// Reload 重新加载配置文件 (解析失败时保留当前配置)
+// 新配置校验失败时, 严格模式 (YAO_RELOAD_STRICT) 返回校验错误并保留当前配置, 否则输出警告后替换
+func Reload() error {
+	return reload(envFile)
+}
+
+// ReloadFrom 从指定配置文件重新加载配置, 成功后 Reload 将读取该文件 (失败时保留当前配置及配置文件)
+func ReloadFrom(envfile string) error {
+	file, err := filepath.Abs(envfile)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(file); err != nil {
+		return err
+	}
+
+	if err := reload(file); err != nil {
+		return err
+	}
+	envFile = file
+	return nil
+}
+
+// reload 读取配置文件并替换当前配置 (失败时恢复环境变量)
+func reload(file string) error {
+	writeMutex.Lock()
+	cfg, err := readReload(file)
+	if err != nil {
+		writeMutex.Unlock()
+		return err
+	}
+	prev := replaceConf(cfg)
+	if file != "" {
+		recordEnvModTime(file)
+	}
+	writeMutex.Unlock()
+
+	applyConf(prev, "reload", "file: "+file)
+	return nil
+}
+
+// readReload 读取配置文件并校验新配置 (调用方须持有 writeMutex, 失败时恢复环境变量)
+func readReload(file string) (Config, error) {
+	markLoaded()
+	restore := saveEnv()
+	if file != "" {
+		if err := godotenv.Overload(file); err != nil {
+			restore()
+			return Config{}, err
+		}
+	}
+	if file != "" && file == envFile {
+		if err := overloadOverlays(envOverlays); err != nil {
+			restore()
+			return Config{}, err
+		}
+	}
+
+	cfg, err := parseEnv()
+	if err != nil {
+		restore()
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		confMutex.RLock()
+		strict := Conf.ReloadStrict
+		confMutex.RUnlock()
+		if strict {
+			restore()
+			log.Error("config reload rejected, keep the current config: %s", err.Error())
+			return Config{}, err
+		}
+		log.Warn("config reloaded with validation errors: %s", err.Error())
+	}
+	return cfg, nil
+}
+
+// swapConf 替换当前配置, 应用运行模式并通知配置变更
+// 新配置依赖当前配置时 (读取, 修改后替换), 请在持有 writeMutex 期间读取并调用 replaceConf, 释放后调用 applyConf
+func swapConf(cfg Config, action, detail string) {
+	writeMutex.Lock()
+	prev := replaceConf(cfg)
+	writeMutex.Unlock()
+	applyConf(prev, action, detail)
+}
+
+// replaceConf 替换当前配置, 返回原配置 (调用方须持有 writeMutex)
+func replaceConf(cfg Config) Config {
+	confMutex.Lock()
+	defer confMutex.Unlock()
+	prev := Conf
+	Conf = cfg
+	return prev
+}
+
+// applyConf 应用运行模式并通知配置变更 (释放 writeMutex 后调用)
+func applyConf(prev Config, action, detail string) {
+	// 与应用运行模式后的配置比较 (开发环境生成的临时密钥不计为变更)
+	ApplyMode()
+	cur := Get()
+	logReload(prev, cur)
+	auditChanges(action, prev, cur, detail)
+	applyReload(newReloadResult(prev, cur), cur)
+}
+
+// saveEnv 保存当前环境变量, 返回恢复函数
+func saveEnv() func() {
+	saved := os.Environ()
+	return func() {
+		os.Clearenv()
+		for _, pair := range saved {
+			kv := strings.SplitN(pair, "=", 2)
+			if len(kv) == 2 {
+				os.Setenv(kv[0], kv[1])
+			}
+		}
+	}
+}
+
+// logReload 输出配置重新加载日志 (根据指纹判断配置是否变更)
+func logReload(prev, cfg Config) {
+	before, after := prev.Fingerprint(), cfg.Fingerprint()
+	if before == after {
+		log.Debug("config reloaded, no changes")
+		return
+	}
+	log.With(log.F{
+		"from":    shortFingerprint(before),
+		"to":      shortFingerprint(after),
+		"changed": len(prev.Diff(cfg)),
+	}).Info("config reloaded")
+}
+
+// Update 以事务方式修改配置: 在配置副本上执行 fn, 校验通过后整体替换当前配置
+// fn 返回错误或校验失败时, 当前配置保持不变; 执行期间其他修改配置的操作 (Reload, Set 等) 等待完成
+// fn 中可以读取配置 (Get, Current), 但不能修改配置 (会等待当前 Update 完成而死锁)
+func Update(fn func(cfg *Config) error) error {
+	writeMutex.Lock()
+	cfg := Get()
+	if err := fn(&cfg); err != nil {
+		writeMutex.Unlock()
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		writeMutex.Unlock()
+		return err
+	}
+	prev := replaceConf(cfg)
+	writeMutex.Unlock()
+
+	applyConf(prev, "update", "")
+	return nil
+}
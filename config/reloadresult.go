@@ -0,0 +1,94 @@
+package config
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/yaoapp/kun/log"
+)
+
+// RestartConfigs 修改后需要重启服务才能生效的配置项 (DB. 开头表示全部数据库配置)
+var RestartConfigs = []string{"Root", "Host", "Port", "Modules", "DisableModules", "Workers", "MaxConcurrentRequests", "PathPrefix", "Mounts", "TrailingSlash", "DB.", "Session."}
+
+// DisruptiveConfigs 可以在运行时生效, 但生效期间需要暂停接收请求的配置项
+var DisruptiveConfigs = []string{"Cert", "Key", "TLSMinVersion", "TLSCipherSuites"}
+
+// ReloadResult 配置重新加载结果
+type ReloadResult struct {
+	Changed          []string // 变更的配置项
+	RestartFields    []string // 需要重启服务才能生效的配置项
+	DisruptiveFields []string // 生效期间需要暂停接收请求的配置项
+}
+
+var lastReload ReloadResult
+var lastReloadMutex sync.RWMutex
+
+// reloadGate 应用中断性配置变更时暂停处理请求
+var reloadGate sync.RWMutex
+
+// newReloadResult 根据变更的配置项生成重新加载结果
+func newReloadResult(prev, cfg Config) ReloadResult {
+	result := ReloadResult{Changed: prev.Diff(cfg), RestartFields: []string{}, DisruptiveFields: []string{}}
+	for _, name := range result.Changed {
+		switch {
+		case matchField(RestartConfigs, name):
+			result.RestartFields = append(result.RestartFields, name)
+		case matchField(DisruptiveConfigs, name):
+			result.DisruptiveFields = append(result.DisruptiveFields, name)
+		}
+	}
+	return result
+}
+
+func matchField(names []string, name string) bool {
+	for _, item := range names {
+		if item == name || strings.HasSuffix(item, ".") && strings.HasPrefix(name, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// Disruptive 是否包含生效期间需要暂停接收请求的变更
+func (r ReloadResult) Disruptive() bool {
+	return len(r.DisruptiveFields) > 0
+}
+
+// RestartRequired 是否包含需要重启服务才能生效的变更
+func (r ReloadResult) RestartRequired() bool {
+	return len(r.RestartFields) > 0
+}
+
+// LastReload 最近一次重新加载配置的结果
+func LastReload() ReloadResult {
+	lastReloadMutex.RLock()
+	defer lastReloadMutex.RUnlock()
+	return lastReload
+}
+
+// AwaitReload 等待中断性配置变更应用完成 (供请求处理中间件调用)
+func AwaitReload() {
+	reloadGate.RLock()
+	reloadGate.RUnlock()
+}
+
+// applyReload 通知配置变更 (包含中断性变更时, 通知期间暂停处理新请求)
+func applyReload(result ReloadResult, cfg Config) {
+	lastReloadMutex.Lock()
+	lastReload = result
+	lastReloadMutex.Unlock()
+
+	if result.RestartRequired() {
+		log.With(log.F{"fields": strings.Join(result.RestartFields, ",")}).Warn("config changes take effect after restart")
+	}
+
+	if !result.Disruptive() {
+		fireReload(cfg)
+		return
+	}
+
+	log.With(log.F{"fields": strings.Join(result.DisruptiveFields, ",")}).Info("pause accepting requests while applying config changes")
+	reloadGate.Lock()
+	defer reloadGate.Unlock()
+	fireReload(cfg)
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// LoadFromURL 从远程地址读取 .env 格式配置, 写入环境变量后加载配置
+// 读取超时时间及最大字节数由当前配置 YAO_CONFIG_FETCH_TIMEOUT, YAO_CONFIG_MAX_SIZE 决定
+func LoadFromURL(url string) (Config, error) {
+	data, err := fetchConfig(url)
+	if err != nil {
+		return Config{}, err
+	}
+
+	vars, err := godotenv.Unmarshal(string(data))
+	if err != nil {
+		return Config{}, fmt.Errorf("config from %s is not a valid env file: %s", url, err.Error())
+	}
+	for key, value := range vars {
+		os.Setenv(key, value)
+	}
+
+	markLoaded()
+	return parseValidEnv()
+}
+
+// fetchConfig 读取远程配置 (远程配置读取均使用此方法, 超时或超过最大字节数时返回错误)
+func fetchConfig(url string) ([]byte, error) {
+	confMutex.RLock()
+	timeout, maxSize := Conf.ConfigFetchTimeout, Conf.ConfigMaxSize.Bytes()
+	confMutex.RUnlock()
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	if maxSize <= 0 {
+		maxSize = 1000 * 1000
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("fetch config from %s timed out after %s (YAO_CONFIG_FETCH_TIMEOUT)", url, timeout)
+		}
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch config from %s failed: %s", url, res.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxSize+1))
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("fetch config from %s timed out after %s (YAO_CONFIG_FETCH_TIMEOUT)", url, timeout)
+		}
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("config from %s exceeds %d bytes (YAO_CONFIG_MAX_SIZE)", url, maxSize)
+	}
+	return data, nil
+}
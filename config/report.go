@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// ReportEntry 校验报告条目
+type ReportEntry struct {
+	Field   string `json:"field,omitempty"` // 相关配置项 (环境变量名称, 无法确定时为空)
+	Code    string `json:"code"`            // 检查项名称 (如 db, jwt_secret, 自定义校验名称)
+	Message string `json:"message"`
+}
+
+// ValidationReport 配置校验报告 (可序列化为 JSON, 供部署工具使用)
+type ValidationReport struct {
+	Errors   []ReportEntry `json:"errors"`
+	Warnings []ReportEntry `json:"warnings"`
+	Info     []ReportEntry `json:"info"`
+}
+
+// 报告级别 (用于 Failed)
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// fieldRe 错误信息中的配置项名称
+var fieldRe = regexp.MustCompile(`\b(?:YAO|XIANG)_[A-Z0-9_]*[A-Z0-9]`)
+
+// Report 执行全部检查 (与 Validate 相同), 返回结构化的校验报告, 不输出日志
+// Info 包含运行模式及与默认值不同的配置项 (敏感信息已隐藏)
+func (c Config) Report() ValidationReport {
+	report := ValidationReport{Errors: []ReportEntry{}, Warnings: []ReportEntry{}, Info: []ReportEntry{}}
+	errors, warnings := c.checks()
+	for _, check := range errors {
+		report.Errors = append(report.Errors, check.entries(c)...)
+	}
+	for _, check := range warnings {
+		report.Warnings = append(report.Warnings, check.entries(c)...)
+	}
+
+	report.Info = append(report.Info, ReportEntry{Field: "YAO_ENV", Code: "mode", Message: fmt.Sprintf("running in %s mode", c.Mode)})
+	nonDefault := c.NonDefault()
+	names := []string{}
+	for name := range nonDefault {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		report.Info = append(report.Info, ReportEntry{Field: name, Code: "non_default", Message: fmt.Sprintf("%s=%s", name, nonDefault[name])})
+	}
+	return report
+}
+
+// entries 执行检查, 返回报告条目
+func (ch check) entries(c Config) []ReportEntry {
+	entries := []ReportEntry{}
+	for _, err := range ch.issues(c) {
+		message := err.Error()
+		entries = append(entries, ReportEntry{Field: fieldRe.FindString(message), Code: ch.code, Message: message})
+	}
+	return entries
+}
+
+// Failed 报告中是否存在不低于指定级别的条目 (error|warning|info, 其他值按 error 处理)
+func (r ValidationReport) Failed(severity string) bool {
+	switch severity {
+	case SeverityInfo:
+		return len(r.Errors)+len(r.Warnings)+len(r.Info) > 0
+	case SeverityWarning:
+		return len(r.Errors)+len(r.Warnings) > 0
+	}
+	return len(r.Errors) > 0
+}
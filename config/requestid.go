@@ -0,0 +1,83 @@
+package config
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// RequestIDFormat 请求 ID 格式
+type RequestIDFormat string
+
+const (
+	// RequestIDUUID UUID v4 格式 (默认)
+	RequestIDUUID RequestIDFormat = "uuid"
+	// RequestIDNanoID 短格式 (21 位 URL 安全字符)
+	RequestIDNanoID RequestIDFormat = "nanoid"
+)
+
+// requestIDMaxLength 复用请求 ID 的最大长度
+const requestIDMaxLength = 128
+
+const nanoidAlphabet = "_-0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+var requestIDRe = regexp.MustCompile(`^[A-Za-z0-9_.:\-]+$`)
+
+// UnmarshalText 解析请求 ID 格式 uuid|nanoid
+func (f *RequestIDFormat) UnmarshalText(text []byte) error {
+	format := RequestIDFormat(strings.ToLower(strings.TrimSpace(string(text))))
+	switch format {
+	case "":
+		*f = RequestIDUUID
+	case RequestIDUUID, RequestIDNanoID:
+		*f = format
+	default:
+		return fmt.Errorf("unsupported request id format %q (supported: uuid, nanoid)", string(text))
+	}
+	return nil
+}
+
+// RequestIDHeaderName 请求 ID 头名称 (未设置时为 X-Request-ID)
+func (s ServiceConfig) RequestIDHeaderName() string {
+	name := strings.TrimSpace(s.RequestIDHeader)
+	if name == "" {
+		return "X-Request-ID"
+	}
+	return http.CanonicalHeaderKey(name)
+}
+
+// RequestID 根据配置返回请求 ID (信任请求 ID 且格式有效时复用, 否则生成新 ID)
+func (s ServiceConfig) RequestID(incoming string) string {
+	incoming = strings.TrimSpace(incoming)
+	if s.RequestIDTrustIncoming && len(incoming) <= requestIDMaxLength && requestIDRe.MatchString(incoming) {
+		return incoming
+	}
+	return s.NewRequestID()
+}
+
+// NewRequestID 按配置格式生成请求 ID
+func (s ServiceConfig) NewRequestID() string {
+	if s.RequestIDFormat == RequestIDNanoID {
+		return newNanoID()
+	}
+	return newUUID()
+}
+
+func newUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant RFC 4122
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func newNanoID() string {
+	b := make([]byte, 21)
+	rand.Read(b)
+	for i := range b {
+		b[i] = nanoidAlphabet[b[i]&63]
+	}
+	return string(b)
+}
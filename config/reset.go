@@ -0,0 +1,31 @@
+package config
+
+import (
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yaoapp/kun/log"
+)
+
+// Reset 关闭日志并从当前环境变量重新加载配置 (用于测试恢复配置, 可重复调用)
+// 不校验配置, 也不通知配置变更
+func Reset() {
+	closeLogOutput()
+	Set(Load())
+}
+
+// Unload 关闭日志并清空配置 (用于测试, 可重复调用)
+func Unload() {
+	closeLogOutput()
+	Set(Config{})
+}
+
+// closeLogOutput 关闭日志, 日志恢复输出到 stderr
+func closeLogOutput() {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	closeLog()
+	log.SetOutput(os.Stderr)
+	logStderrWrapped = false
+	gin.DefaultWriter = os.Stdout
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// ResolveOptions 配置来源 (优先级从低到高: Defaults < Files < 系统环境变量 < Flags)
+type ResolveOptions struct {
+	Defaults map[string]string // 默认值 (覆盖结构体标签中的默认值), 名称格式与 LoadFromArgs 相同
+	Files    []string          // .env 配置文件 (靠后的文件优先)
+	UseOSEnv bool              // 是否读取系统环境变量
+	Flags    map[string]string // 命令行参数, 名称格式与 LoadFromArgs 相同
+}
+
+// Resolve 按优先级合并各来源的配置并校验, 返回配置及解析或校验错误
+// 不修改系统环境变量; Defaults 及 Flags 中无法识别的名称返回错误
+func Resolve(opts ResolveOptions) (Config, error) {
+	markLoaded()
+	keys := argKeys()
+	vars := map[string]string{}
+
+	if err := resolveNamed(vars, keys, opts.Defaults, "defaults"); err != nil {
+		return Config{}, err
+	}
+
+	if len(opts.Files) > 0 {
+		files, err := godotenv.Read(opts.Files...)
+		if err != nil {
+			return Config{}, err
+		}
+		for key, value := range applyEnvPrefix(files) {
+			vars[key] = value
+		}
+	}
+
+	if opts.UseOSEnv {
+		for key, value := range environ() {
+			vars[key] = value
+		}
+	}
+
+	if err := resolveNamed(vars, keys, opts.Flags, "flags"); err != nil {
+		return Config{}, err
+	}
+
+	cfg, err := parse(vars)
+	if err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// resolveNamed 按名称对照表写入配置项 (无法识别的名称返回错误)
+func resolveNamed(vars map[string]string, keys map[string]string, values map[string]string, source string) error {
+	unknown := []string{}
+	for key, value := range values {
+		name, has := keys[strings.ToUpper(strings.TrimSpace(key))]
+		if !has {
+			unknown = append(unknown, key)
+			continue
+		}
+		vars[name] = value
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown config in %s: %s", source, strings.Join(unknown, ", "))
+	}
+	return nil
+}
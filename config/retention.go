@@ -0,0 +1,31 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// RetentionPolicy 返回数据目录临时数据的保留时长和清理间隔
+func (c Config) RetentionPolicy() (time.Duration, time.Duration) {
+	return c.DataRetention, c.DataCleanupInterval
+}
+
+// validateRetention 检查数据保留策略
+func (c Config) validateRetention() error {
+	errs := Errors{}
+	if c.DataRetention < 0 {
+		errs = append(errs, fmt.Errorf("YAO_DATA_RETENTION must not be negative (got %s)", c.DataRetention))
+	}
+	if c.DataRetention > 0 && c.DataCleanupInterval <= 0 {
+		errs = append(errs, fmt.Errorf("YAO_DATA_CLEANUP_INTERVAL must be greater than 0 when YAO_DATA_RETENTION is set (got %s)", c.DataCleanupInterval))
+	}
+	return errs.Err()
+}
+
+// RetentionCutoff 临时数据过期时间点 (早于该时间的数据可以清理, 未设置保留时长返回零值)
+func (c Config) RetentionCutoff() time.Time {
+	if c.DataRetention <= 0 {
+		return time.Time{}
+	}
+	return currentClock().Now().Add(-c.DataRetention)
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yaoapp/kun/log"
+)
+
+// Backoff 第 attempt 次重试 (从 0 开始) 前的等待时间
+func (r RetryConfig) Backoff(attempt int) time.Duration {
+	delay := r.Initial
+	if delay <= 0 {
+		delay = 500 * time.Millisecond
+	}
+	multiplier := r.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	for i := 0; i < attempt; i++ {
+		delay = time.Duration(float64(delay) * multiplier)
+		if r.Max > 0 && delay >= r.Max {
+			return r.Max
+		}
+	}
+	if r.Max > 0 && delay > r.Max {
+		return r.Max
+	}
+	return delay
+}
+
+// WaitForDependencies 等待数据库连接成功 (YAO_WAIT_FOR_DB), 按 Retry 配置退避重试, 超过 YAO_WAIT_FOR_DB_TIMEOUT 返回错误
+// 数据库检查使用 RegisterHealthCheck 注册的 db 检查项; 未启用 YAO_WAIT_FOR_DB 时直接返回
+func (c Config) WaitForDependencies(ctx context.Context) error {
+	if !c.WaitForDB {
+		return nil
+	}
+
+	if c.WaitForDBTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.WaitForDBTimeout)
+		defer cancel()
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := runHealthCheck(ctx, "db")
+		if err == nil {
+			return nil
+		}
+		if !healthCheckRegistered("db") {
+			return err
+		}
+
+		delay := c.Retry.Backoff(attempt)
+		log.Warn("database is not ready (%s), retry in %s", err.Error(), delay)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("database is not ready after %s (YAO_WAIT_FOR_DB_TIMEOUT): %s", c.WaitForDBTimeout, err.Error())
+		case <-currentClock().After(delay):
+		}
+	}
+}
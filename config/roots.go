@@ -0,0 +1,193 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// RootDirs 应用目录 (名称 => 相对于应用根目录的路径)
+var RootDirs = map[string]string{
+	"api":      "apis",
+	"model":    "models",
+	"flow":     "flows",
+	"plugin":   "plugins",
+	"table":    "tables",
+	"chart":    "charts",
+	"page":     "pages",
+	"workflow": "workflows",
+	"importer": "imports",
+	"script":   "scripts",
+	"lib":      "libs",
+	"data":     "data",
+	"db":       "db",
+	"ui":       "ui",
+}
+
+// Roots 返回应用各目录的绝对路径
+func (c Config) Roots() map[string]string {
+	roots := map[string]string{}
+	for name := range RootDirs {
+		roots[name] = c.RootOf(name)
+	}
+	return roots
+}
+
+// RootOf 返回指定名称的应用目录 (未知名称返回空字符串)
+func (c Config) RootOf(name string) string {
+	dir, has := RootDirs[name]
+	if !has {
+		return ""
+	}
+	root, err := filepath.Abs(c.Root)
+	if err != nil {
+		root = c.Root
+	}
+	return filepath.Join(root, dir)
+}
+
+// EnsureDirs 创建不存在的应用目录 (RootDirs, 权限 os.ModePerm), 已存在的目录保持不变, 返回遇到的第一个错误
+// 应用根目录未设置时不做处理
+func (c Config) EnsureDirs() error {
+	if c.Root == "" {
+		return nil
+	}
+
+	names := make([]string, 0, len(RootDirs))
+	for name := range RootDirs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if RootDirs[name] == "" {
+			continue
+		}
+		dir := c.RootOf(name)
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return fmt.Errorf("can't create the %s directory %s: %s", name, dir, err.Error())
+		}
+	}
+	return nil
+}
+
+// SetRoot 设定应用根目录 (各应用目录随之变更)
+func SetRoot(root string) error {
+	writeMutex.Lock()
+	defer writeMutex.Unlock()
+	if err := setRoot(root); err != nil {
+		return err
+	}
+	publishConf()
+	return nil
+}
+
+// setRoot 设定应用根目录 (调用方须持有 writeMutex)
+func setRoot(root string) error {
+	fullpath, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(fullpath)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", fullpath)
+	}
+
+	confMutex.Lock()
+	Conf.Root = fullpath
+	confMutex.Unlock()
+	return nil
+}
+
+// SetRootWithEnv 设定应用根目录, 如根目录下存在 .env 文件, 加载并覆盖当前配置
+func SetRootWithEnv(root string) (Config, error) {
+	writeMutex.Lock()
+	if err := setRoot(root); err != nil {
+		writeMutex.Unlock()
+		return Get(), err
+	}
+	publishConf()
+
+	cfg := Get()
+	file := filepath.Join(cfg.Root, ".env")
+	if _, err := os.Stat(file); errors.Is(err, os.ErrNotExist) {
+		writeMutex.Unlock()
+		return cfg, nil
+	}
+
+	if err := godotenv.Overload(file); err != nil {
+		writeMutex.Unlock()
+		return cfg, err
+	}
+
+	next, err := parse(environ())
+	if err != nil {
+		writeMutex.Unlock()
+		return cfg, err
+	}
+	next.Root = cfg.Root
+
+	confMutex.Lock()
+	Conf = next
+	envFile = file
+	confMutex.Unlock()
+	writeMutex.Unlock()
+
+	ApplyMode()
+	publishConf()
+	return Get(), nil
+}
+
+// IsUnderRoot 检查路径是否位于应用目录内 (防止目录穿越), 返回匹配的目录名称
+// 优先匹配具体的应用目录 (如 data, models), 其次匹配应用根目录 (root)
+func (c Config) IsUnderRoot(path string) (bool, string) {
+	fullpath, err := filepath.Abs(path)
+	if err != nil {
+		return false, ""
+	}
+	fullpath = resolvePath(fullpath)
+
+	matched, length := "", -1
+	for name, dir := range c.Roots() {
+		dir = resolvePath(dir)
+		if pathWithin(fullpath, dir) && len(dir) > length {
+			matched, length = name, len(dir)
+		}
+	}
+	if matched != "" {
+		return true, matched
+	}
+
+	root, err := filepath.Abs(c.Root)
+	if err == nil && pathWithin(fullpath, resolvePath(root)) {
+		return true, "root"
+	}
+	return false, ""
+}
+
+// pathWithin 路径 path 是否位于目录 dir 内 (包括 dir 本身)
+func pathWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// resolvePath 解析符号链接 (路径不存在时返回清理后的路径)
+func resolvePath(path string) string {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	return resolved
+}
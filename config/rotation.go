@@ -0,0 +1,200 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotateTimeFormat 轮转后的日志文件名中的时间格式 (如 yao-2022-01-02T15-04-05.000.log)
+const rotateTimeFormat = "2006-01-02T15-04-05.000"
+
+// rotationEnabled 是否启用日志文件轮转
+func (c Config) rotationEnabled() bool {
+	return c.LogMaxSize > 0 || c.LogMaxBackups > 0 || c.LogMaxAge > 0
+}
+
+// ValidateRotation 检查日志轮转配置是否一致
+// 日志文件由 OpenLog 按 YAO_LOG_MAX_SIZE 轮转 (rotateWriter), YAO_LOG_MAX_BACKUPS 与 YAO_LOG_MAX_AGE 只清理轮转后的文件, 单独设置时日志文件不会轮转
+func (c Config) ValidateRotation() error {
+	errs := Errors{}
+	for _, setting := range []struct {
+		name  string
+		value int
+	}{
+		{"YAO_LOG_MAX_SIZE", c.LogMaxSize},
+		{"YAO_LOG_MAX_BACKUPS", c.LogMaxBackups},
+		{"YAO_LOG_MAX_AGE", c.LogMaxAge},
+	} {
+		if setting.value < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative (got %d)", setting.name, setting.value))
+		}
+	}
+
+	if c.LogMaxSize == 0 && c.LogMaxBackups > 0 {
+		errs = append(errs, fmt.Errorf("YAO_LOG_MAX_BACKUPS only limits rotated files, but rotation is disabled (set YAO_LOG_MAX_SIZE to rotate the log file)"))
+	}
+	if c.LogMaxSize == 0 && c.LogMaxAge > 0 {
+		errs = append(errs, fmt.Errorf("YAO_LOG_MAX_AGE only removes rotated files, but rotation is disabled (set YAO_LOG_MAX_SIZE to rotate the log file)"))
+	}
+
+	if c.rotationEnabled() && !c.logToFile() {
+		errs = append(errs, fmt.Errorf("log rotation is configured, but no log file is set in YAO_LOG or YAO_LOG_FALLBACKS, the log is never rotated"))
+	}
+	return errs.Err()
+}
+
+// logToFile 日志地址中是否包含文件
+func (c Config) logToFile() bool {
+	for _, dest := range c.logDestinations() {
+		if d, err := ParseLogDestination(dest); err == nil && d.Kind == LogDestFile {
+			return true
+		}
+	}
+	return false
+}
+
+// rotateWriter 按大小轮转的日志文件输出 (YAO_LOG_MAX_SIZE, 轮转后按 YAO_LOG_MAX_BACKUPS 及 YAO_LOG_MAX_AGE 清理)
+type rotateWriter struct {
+	mutex      sync.Mutex
+	file       *os.File // 当前日志文件 (关闭后为 nil)
+	path       string
+	size       int64
+	maxSize    int64 // 轮转大小 (字节, 0 不轮转)
+	maxBackups int
+	maxAge     time.Duration
+}
+
+// newRotateWriter 创建轮转日志输出, 并清理过期的轮转文件
+func newRotateWriter(file *os.File, cfg Config) (*rotateWriter, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	w := &rotateWriter{file: file, path: file.Name(), size: info.Size()}
+	w.configure(cfg)
+	w.mutex.Lock()
+	w.cleanup()
+	w.mutex.Unlock()
+	return w, nil
+}
+
+// configure 更新轮转配置 (重新加载日志且日志地址未变更时使用)
+func (w *rotateWriter) configure(cfg Config) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.maxSize = int64(cfg.LogMaxSize) * 1024 * 1024
+	w.maxBackups = cfg.LogMaxBackups
+	w.maxAge = time.Duration(cfg.LogMaxAge) * 24 * time.Hour
+}
+
+// Write 写入日志 (写入后超过轮转大小时先轮转日志文件, 轮转失败时继续写入当前文件)
+func (w *rotateWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			fmt.Fprintf(os.Stderr, "log file %s can't be rotated. %s\n", w.path, err.Error())
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// rotate 将当前日志文件重命名为带时间的轮转文件, 并重新创建日志文件
+func (w *rotateWriter) rotate() error {
+	backup := w.backupName(currentClock().Now())
+	if err := os.Rename(w.path, backup); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(w.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err // 继续写入已重命名的文件
+	}
+
+	w.file.Close()
+	w.file = file
+	w.size = 0
+	w.cleanup()
+	return nil
+}
+
+// backupName 轮转后的文件名 (如 /var/log/yao-2022-01-02T15-04-05.000.log)
+func (w *rotateWriter) backupName(t time.Time) string {
+	ext := filepath.Ext(w.path)
+	name := strings.TrimSuffix(w.path, ext)
+	return fmt.Sprintf("%s-%s%s", name, t.Format(rotateTimeFormat), ext)
+}
+
+// rotateBackup 轮转后的日志文件
+type rotateBackup struct {
+	path string
+	time time.Time
+}
+
+// backups 轮转后的日志文件列表 (由新到旧)
+func (w *rotateWriter) backups() []rotateBackup {
+	ext := filepath.Ext(w.path)
+	prefix := filepath.Base(strings.TrimSuffix(w.path, ext)) + "-"
+	entries, err := os.ReadDir(filepath.Dir(w.path))
+	if err != nil {
+		return nil
+	}
+
+	backups := []rotateBackup{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		t, err := time.Parse(rotateTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext))
+		if err != nil {
+			continue
+		}
+		backups = append(backups, rotateBackup{path: filepath.Join(filepath.Dir(w.path), name), time: t})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].time.After(backups[j].time) })
+	return backups
+}
+
+// cleanup 删除超出保留数量或保留天数的轮转文件
+func (w *rotateWriter) cleanup() {
+	if w.maxBackups <= 0 && w.maxAge <= 0 {
+		return
+	}
+
+	now := currentClock().Now()
+	for i, backup := range w.backups() {
+		expired := w.maxAge > 0 && now.Sub(backup.time) > w.maxAge
+		if (w.maxBackups > 0 && i >= w.maxBackups) || expired {
+			if err := os.Remove(backup.path); err != nil {
+				fmt.Fprintf(os.Stderr, "rotated log file %s can't be removed. %s\n", backup.path, err.Error())
+			}
+		}
+	}
+}
+
+// Close 同步并关闭日志文件
+func (w *rotateWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	w.file.Sync()
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/yaoapp/kun/log"
+)
+
+// secretFiles 从密钥文件读取的环境变量 (重新加载时可被密钥文件覆盖)
+var secretFiles = map[string]bool{}
+var secretFilesMutex sync.Mutex
+
+// LoadSecretsFromDir 从目录中读取密钥文件 (文件名为敏感配置项的环境变量名称, 如 YAO_JWT_SECRET)
+// 文件内容 (去除首尾空白) 写入对应的环境变量; 已通过环境变量设置的配置项不会被覆盖, 无法对应配置项的文件将被忽略
+// 目录不存在时不做处理
+func LoadSecretsFromDir(dir string) error {
+	_, err := loadSecretsFromDir(dir)
+	return err
+}
+
+func loadSecretsFromDir(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	names := map[string]bool{}
+	for _, field := range (&Config{}).fields() {
+		if field.secret() {
+			names[envVarName(field.Env)] = true
+		}
+	}
+
+	secretFilesMutex.Lock()
+	defer secretFilesMutex.Unlock()
+
+	loaded := 0
+	errs := Errors{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !names[name] {
+			continue
+		}
+		if value, has := os.LookupEnv(name); has && value != "" && !secretFiles[name] {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("can't read secret file %s: %s", filepath.Join(dir, name), err.Error()))
+			continue
+		}
+		os.Setenv(name, strings.TrimSpace(string(data)))
+		secretFiles[name] = true
+		loaded++
+	}
+	return loaded, errs.Err()
+}
+
+// devSecrets 开发环境自动生成的临时密钥 (环境变量名称 => 密钥, 进程内保持不变, 重新加载配置后 JWT 等仍然有效)
+var devSecrets = map[string]string{}
+var devSecretsMutex sync.Mutex
+
+// FillDevSecrets 为未设置的敏感配置项 (如 YAO_JWT_SECRET, YAO_DB_AESKEY) 生成随机的临时密钥, 仅用于开发环境
+// 生产环境不会自动生成密钥; 临时密钥在进程退出后失效, 使用其加密的数据将无法解密
+func (c *Config) FillDevSecrets() {
+	if c.Mode == "production" {
+		return
+	}
+
+	devSecretsMutex.Lock()
+	defer devSecretsMutex.Unlock()
+	for _, field := range c.fields() {
+		if !field.secret() || field.Value.Kind() != reflect.String || field.Value.String() != "" {
+			continue
+		}
+
+		secret, has := devSecrets[field.Env]
+		if !has {
+			buf := make([]byte, 16)
+			if _, err := rand.Read(buf); err != nil {
+				log.Error("Can't generate an ephemeral %s: %s", field.Env, err.Error())
+				continue
+			}
+			secret = hex.EncodeToString(buf)
+			devSecrets[field.Env] = secret
+			log.Warn("%s is not set, using an ephemeral random value in %s mode (data and tokens secured with it are lost on restart)", field.Env, c.Mode)
+		}
+		field.Value.SetString(secret)
+	}
+}
+
+// parseEnv 根据当前环境变量解析配置 (先应用 YAO_ENV_B64, 生产环境自动读取 YAO_SECRETS_DIR 中的密钥文件)
+func parseEnv() (Config, error) {
+	if err := applyEnvBlob(); err != nil {
+		return Config{}, err
+	}
+
+	cfg, err := parse(environ())
+	if err != nil || cfg.Mode != "production" || cfg.SecretsDir == "" {
+		return cfg, err
+	}
+
+	loaded, err := loadSecretsFromDir(cfg.SecretsDir)
+	if err != nil {
+		log.Warn("%s", err.Error())
+	}
+	if loaded == 0 {
+		return cfg, nil
+	}
+	return parse(environ())
+}
+
+// parseValidEnv 读取环境变量配置并校验 (供 LoadINI, LoadFromURL 等返回错误的加载方法使用)
+func parseValidEnv() (Config, error) {
+	cfg, err := parseEnv()
+	if err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/yaoapp/kun/log"
+)
+
+// workersLimit 工作协程数量超过 GOMAXPROCS 的倍数时输出警告
+const workersLimit = 64
+
+// WorkerCount 工作协程数量 (未设置时使用 GOMAXPROCS, 最小为 1)
+func (c Config) WorkerCount() int {
+	procs := runtime.GOMAXPROCS(0)
+	if c.Workers <= 0 {
+		return procs
+	}
+	if c.Workers > procs*workersLimit {
+		log.Warn("YAO_WORKERS %d is much higher than GOMAXPROCS %d", c.Workers, procs)
+	}
+	return c.Workers
+}
+
+// DrainPolicy 返回关闭服务时的流量摘除等待时长和请求完成等待时长
+// 关闭服务时先将就绪状态设为失败, 等待 DrainDelay 后停止接收新连接, 最多等待 DrainTimeout 处理中的请求完成
+func (s ServiceConfig) DrainPolicy() (time.Duration, time.Duration) {
+	return s.DrainDelay, s.DrainTimeout
+}
+
+// 请求头最大字节数允许范围
+const (
+	minHeaderBytes = 4 << 10
+	maxHeaderBytes = 16 << 20
+)
+
+// MaxHeaderBytes 请求头最大字节数 (用于 http.Server.MaxHeaderBytes, 未设置时使用 Go 默认值)
+func (s ServiceConfig) MaxHeaderBytes() int {
+	if s.MaxHeaderSize <= 0 {
+		return http.DefaultMaxHeaderBytes
+	}
+	return int(s.MaxHeaderSize)
+}
+
+// trailingSlashPolicies 路径结尾 / 处理方式
+var trailingSlashPolicies = []string{"redirect", "strict", "ignore"}
+
+// TrailingSlashPolicy 路径结尾 / 处理方式 (未设置时为 redirect)
+//
+//	redirect 重定向到匹配的路由 (gin 默认, POST 请求重定向后会丢失请求内容)
+//	strict   不重定向, /api/foo/ 与 /api/foo 为不同的路由
+//	ignore   路由匹配前去除结尾 /, 两种路径均匹配同一路由
+func (s ServiceConfig) TrailingSlashPolicy() string {
+	policy := strings.ToLower(strings.TrimSpace(s.TrailingSlash))
+	if policy == "" {
+		return "redirect"
+	}
+	return policy
+}
+
+// defaultMultipartMemory gin 默认的 multipart 内存缓存大小
+const defaultMultipartMemory = 32 << 20
+
+// MultipartMaxMemory 上传文件时内存缓存的最大字节数 (未设置时使用 gin 默认值 32MiB)
+func (s ServiceConfig) MultipartMaxMemory() int64 {
+	if s.MultipartMemory <= 0 {
+		return defaultMultipartMemory
+	}
+	return s.MultipartMemory.Bytes()
+}
+
+// RecoverPolicy 返回请求处理 panic 时是否输出调用栈, 以及是否在响应中返回错误信息
+func (s ServiceConfig) RecoverPolicy() (bool, bool) {
+	return s.RecoverStack, s.RecoverExposeError
+}
+
+// LogBodyFor 请求路径是否需要记录请求及响应内容, 以及记录的最大字节数
+func (s ServiceConfig) LogBodyFor(path string) (bool, int64) {
+	if !s.LogBodies {
+		return false, 0
+	}
+	if len(s.LogBodiesPaths) == 0 {
+		return true, s.LogBodiesMaxBytes.Bytes()
+	}
+	for _, prefix := range s.LogBodiesPaths {
+		prefix = strings.TrimSpace(prefix)
+		if prefix != "" && strings.HasPrefix(path, prefix) {
+			return true, s.LogBodiesMaxBytes.Bytes()
+		}
+	}
+	return false, 0
+}
+
+// ConcurrencyLimiter 创建同时处理请求数的信号量 (容量为 YAO_MAX_CONCURRENT_REQUESTS, 未设置时返回 nil)
+// 每次调用创建新的信号量, 服务启动时创建一次并在全部请求间共享
+func (s ServiceConfig) ConcurrencyLimiter() chan struct{} {
+	if s.MaxConcurrentRequests <= 0 {
+		return nil
+	}
+	return make(chan struct{}, s.MaxConcurrentRequests)
+}
+
+// validateService 检查服务配置
+func (c Config) validateService() error {
+	errs := Errors{}
+	if c.Workers < 0 {
+		errs = append(errs, fmt.Errorf("YAO_WORKERS must not be negative (got %d)", c.Workers))
+	}
+	if c.DrainDelay < 0 {
+		errs = append(errs, fmt.Errorf("YAO_DRAIN_DELAY must not be negative (got %s)", c.DrainDelay))
+	}
+	if c.DrainTimeout < 0 {
+		errs = append(errs, fmt.Errorf("YAO_DRAIN_TIMEOUT must not be negative (got %s)", c.DrainTimeout))
+	}
+	if c.MaxHeaderSize != 0 && (c.MaxHeaderSize < minHeaderBytes || c.MaxHeaderSize > maxHeaderBytes) {
+		errs = append(errs, fmt.Errorf("YAO_MAX_HEADER_BYTES must be between 4KiB and 16MiB (got %d bytes)", c.MaxHeaderSize))
+	}
+	if c.LogBodies && c.Mode == "production" && !c.AllowBodyLogging {
+		errs = append(errs, fmt.Errorf("YAO_LOG_BODIES is not allowed in production, set YAO_ALLOW_BODY_LOGGING=true to enable it"))
+	}
+	if c.LogBodies && c.LogBodiesMaxBytes <= 0 {
+		errs = append(errs, fmt.Errorf("YAO_LOG_BODIES_MAX_BYTES must be greater than 0 when YAO_LOG_BODIES is set"))
+	}
+	if _, err := c.ParsedAllow(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.MaxConcurrentRequests < 0 {
+		errs = append(errs, fmt.Errorf("YAO_MAX_CONCURRENT_REQUESTS must not be negative (got %d)", c.MaxConcurrentRequests))
+	}
+	if !contains(trailingSlashPolicies, c.TrailingSlashPolicy()) {
+		errs = append(errs, fmt.Errorf("YAO_TRAILING_SLASH must be one of %s (got %q)", strings.Join(trailingSlashPolicies, ", "), c.TrailingSlash))
+	}
+	if c.MultipartMemory < 0 {
+		errs = append(errs, fmt.Errorf("YAO_MULTIPART_MAX_MEMORY must be positive (got %d bytes)", c.MultipartMemory))
+	}
+	if c.RequestTimeout < 0 {
+		errs = append(errs, fmt.Errorf("YAO_REQUEST_TIMEOUT must not be negative (got %s)", c.RequestTimeout))
+	}
+	if c.ConcurrencyWait < 0 {
+		errs = append(errs, fmt.Errorf("YAO_CONCURRENCY_WAIT must not be negative (got %s)", c.ConcurrencyWait))
+	}
+	if c.PathPrefix != "" && (!strings.HasPrefix(c.PathPrefix, "/") || strings.HasSuffix(c.PathPrefix, "/")) {
+		errs = append(errs, fmt.Errorf("YAO_BASE_PATH must start with / and must not end with / (got %q)", c.PathPrefix))
+	}
+	if c.ErrorFormat != "" && !contains(errorFormats, strings.ToLower(strings.TrimSpace(c.ErrorFormat))) {
+		errs = append(errs, fmt.Errorf("YAO_ERROR_FORMAT must be one of %s (got %q)", strings.Join(errorFormats, ", "), c.ErrorFormat))
+	}
+	return errs.Err()
+}
+
+// serviceWarnings 服务配置警告 (生产环境返回 panic 错误信息)
+func (c Config) serviceWarnings() error {
+	errs := Errors{}
+	if c.RecoverExposeError && c.Mode == "production" {
+		errs = append(errs, fmt.Errorf("YAO_RECOVER_EXPOSE_ERROR is enabled in production, panic errors will be returned to clients"))
+	}
+	if c.JSONEncoder != "" && !contains(jsonEncoders, strings.ToLower(strings.TrimSpace(c.JSONEncoder))) {
+		errs = append(errs, fmt.Errorf("YAO_JSON_ENCODER must be one of %s (got %q), stdlib will be used", strings.Join(jsonEncoders, ", "), c.JSONEncoder))
+	}
+	return errs.Err()
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// validateSession 检查会话服务器配置 (与服务监听地址冲突时返回错误)
+func (c Config) validateSession() error {
+	if !c.Session.Hosting || c.Session.IsCLI {
+		return nil
+	}
+
+	errs := Errors{}
+	if c.Session.Port == c.Port && hostsOverlap(c.Session.Host, c.Host) {
+		errs = append(errs, fmt.Errorf(
+			"session server %s (XIANG_SESSION_HOST, XIANG_SESSION_PORT) conflicts with service %s (YAO_HOST, YAO_PORT)",
+			net.JoinHostPort(c.Session.Host, strconv.Itoa(c.Session.Port)),
+			net.JoinHostPort(c.Host, strconv.Itoa(c.Port)),
+		))
+	}
+	return errs.Err()
+}
+
+// sessionWarnings 会话服务器配置警告 (托管模式监听公网地址)
+func (c Config) sessionWarnings() error {
+	if c.Session.Hosting && !c.Session.IsCLI && publicHost(c.Session.Host) {
+		return fmt.Errorf("The session server listens on %s without TLS, bind it to a private interface (XIANG_SESSION_HOST)", c.Session.Host)
+	}
+	return nil
+}
+
+// hostsOverlap 两个监听地址是否可能冲突 (相同地址, 或其中之一监听全部地址)
+func hostsOverlap(a, b string) bool {
+	if a == b {
+		return true
+	}
+	for _, host := range []string{a, b} {
+		if host == "" {
+			return true
+		}
+		if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+			return true
+		}
+	}
+	ipa, ipb := net.ParseIP(a), net.ParseIP(b)
+	return ipa != nil && ipb != nil && ipa.Equal(ipb)
+}
+
+// publicHost 监听地址是否可能对公网开放 (监听全部地址, 或非回环、非内网地址)
+func publicHost(host string) bool {
+	switch host {
+	case "":
+		return true
+	case "localhost":
+		return false
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsUnspecified() {
+		return true
+	}
+	return !ip.IsLoopback() && !isPrivateIP(ip)
+}
+
+// isPrivateIP 是否为内网地址 (RFC 1918, RFC 4193 及链路本地地址)
+func isPrivateIP(ip net.IP) bool {
+	for _, cidr := range []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7", "169.254.0.0/16", "fe80::/10"} {
+		_, network, _ := net.ParseCIDR(cidr)
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
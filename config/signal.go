@@ -0,0 +1,60 @@
+//go:build !windows
+// +build !windows
+
+package config
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/yaoapp/kun/log"
+)
+
+// hangupStop 停止监听 SIGHUP (未监听时为 nil)
+var hangupStop chan struct{}
+var hangupMutex sync.Mutex
+
+// InstallSignalHandlers 监听 SIGHUP 信号, 收到时重新打开日志文件并重新加载证书
+// 配合 logrotate 等外部轮转工具使用 (轮转工具重命名日志文件后发送 SIGHUP); 重复调用时只监听一次
+func InstallSignalHandlers() {
+	hangupMutex.Lock()
+	defer hangupMutex.Unlock()
+	if hangupStop != nil {
+		return
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	stop := make(chan struct{})
+	hangupStop = stop
+	go func() {
+		for {
+			select {
+			case <-signals:
+				onHangup()
+			case <-stop:
+				signal.Stop(signals)
+				return
+			}
+		}
+	}()
+}
+
+// UninstallSignalHandlers 停止监听 SIGHUP 信号
+func UninstallSignalHandlers() {
+	hangupMutex.Lock()
+	defer hangupMutex.Unlock()
+	if hangupStop != nil {
+		close(hangupStop)
+		hangupStop = nil
+	}
+}
+
+// onHangup 收到 SIGHUP 时重新打开日志文件并重新加载证书
+func onHangup() {
+	log.Info("SIGHUP received, reopen the log and reload the certificate")
+	ReopenLog()
+	reloadCert(Get())
+}
@@ -0,0 +1,7 @@
+package config
+
+// InstallSignalHandlers 监听 SIGHUP 信号 (Windows 不支持, 不做处理)
+func InstallSignalHandlers() {}
+
+// UninstallSignalHandlers 停止监听 SIGHUP 信号 (Windows 不支持, 不做处理)
+func UninstallSignalHandlers() {}
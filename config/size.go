@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ByteSize 字节大小
+// 支持 SI 单位 KB, MB, GB, TB (按 1000 换算), IEC 单位 KiB, MiB, GiB, TiB (按 1024 换算),
+// 简写 K, M, G, T (按 1024 换算), 无单位或 B 为字节数
+type ByteSize int64
+
+var byteUnits = []struct {
+	suffix string
+	size   int64
+}{
+	{"kib", 1 << 10}, {"mib", 1 << 20}, {"gib", 1 << 30}, {"tib", 1 << 40},
+	{"kb", 1e3}, {"mb", 1e6}, {"gb", 1e9}, {"tb", 1e12},
+	{"k", 1 << 10}, {"m", 1 << 20}, {"g", 1 << 30}, {"t", 1 << 40},
+	{"b", 1},
+}
+
+// UnmarshalText 解析字节大小 (例: 8192, 512K, 10MB, 1GiB)
+func (size *ByteSize) UnmarshalText(text []byte) error {
+	value := strings.ToLower(strings.TrimSpace(string(text)))
+	if value == "" {
+		*size = 0
+		return nil
+	}
+
+	unit := int64(1)
+	for _, u := range byteUnits {
+		if strings.HasSuffix(value, u.suffix) {
+			unit = u.size
+			value = strings.TrimSpace(strings.TrimSuffix(value, u.suffix))
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n < 0 {
+		return fmt.Errorf("invalid size %q (example: 8192, 512K, 10MB, 1GiB)", string(text))
+	}
+	*size = ByteSize(n * float64(unit))
+	return nil
+}
+
+// MarshalText 输出字节大小 (能整除时使用 IEC 单位)
+func (size ByteSize) MarshalText() ([]byte, error) {
+	for _, u := range []struct {
+		suffix string
+		size   int64
+	}{{"TiB", 1 << 40}, {"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10}} {
+		if size > 0 && int64(size)%u.size == 0 {
+			return []byte(strconv.FormatInt(int64(size)/u.size, 10) + u.suffix), nil
+		}
+	}
+	return []byte(strconv.FormatInt(int64(size), 10)), nil
+}
+
+// Bytes 字节数
+func (size ByteSize) Bytes() int64 {
+	return int64(size)
+}
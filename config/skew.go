@@ -0,0 +1,62 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"time"
+)
+
+// CheckValidity 检查当前时间是否在有效期内 (两端各放宽 YAO_CLOCK_SKEW, 零值表示不限制)
+func (c Config) CheckValidity(notBefore, notAfter time.Time) error {
+	now := currentClock().Now()
+	if !notBefore.IsZero() && now.Add(c.ClockSkew).Before(notBefore) {
+		return fmt.Errorf("not valid before %s (YAO_CLOCK_SKEW=%s)", notBefore.Format(time.RFC3339), c.ClockSkew)
+	}
+	if !notAfter.IsZero() && now.Add(-c.ClockSkew).After(notAfter) {
+		return fmt.Errorf("expired at %s (YAO_CLOCK_SKEW=%s)", notAfter.Format(time.RFC3339), c.ClockSkew)
+	}
+	return nil
+}
+
+// CheckTLS 检查 HTTPS 证书是否在有效期内 (未设置证书返回 nil)
+func (c Config) CheckTLS() error {
+	if !c.HTTPS() {
+		return nil
+	}
+
+	leaf, err := c.leafCert()
+	if err != nil {
+		return err
+	}
+	if err := c.CheckValidity(leaf.NotBefore, leaf.NotAfter); err != nil {
+		return fmt.Errorf("YAO_CERT %s is %s", c.Cert, err.Error())
+	}
+	return nil
+}
+
+// leafCert 读取当前 HTTPS 证书 (未加载时从证书文件读取)
+func (s ServiceConfig) leafCert() (*x509.Certificate, error) {
+	cert, ok := certCache.Load().(*tls.Certificate)
+	if !ok {
+		pair, err := tls.LoadX509KeyPair(s.Cert, s.Key)
+		if err != nil {
+			return nil, err
+		}
+		cert = &pair
+	}
+	return x509.ParseCertificate(cert.Certificate[0])
+}
+
+// validateClockSkew 检查时钟偏差配置
+func (c Config) validateClockSkew() error {
+	if c.ClockSkew < 0 {
+		return fmt.Errorf("YAO_CLOCK_SKEW must not be negative, got %s", c.ClockSkew)
+	}
+	return nil
+}
+
+// tlsWarnings 证书有效期警告
+func (c Config) tlsWarnings() error {
+	return c.CheckTLS()
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"sync"
+)
+
+// snapshotLimit 最多保留的快照数量
+const snapshotLimit = 10
+
+type snapshot struct {
+	id   int
+	conf Config
+}
+
+// snapshots 配置快照 (按最近使用排序, 末尾为最近使用)
+var snapshots = []snapshot{}
+var snapshotID = 0
+var snapshotMutex sync.Mutex
+
+// Snapshot 保存当前配置快照, 返回快照ID (超出数量限制时淘汰最久未使用的快照)
+func Snapshot() int {
+	conf := Get()
+
+	snapshotMutex.Lock()
+	defer snapshotMutex.Unlock()
+
+	snapshotID++
+	snapshots = append(snapshots, snapshot{id: snapshotID, conf: conf})
+	if len(snapshots) > snapshotLimit {
+		snapshots = snapshots[len(snapshots)-snapshotLimit:]
+	}
+	return snapshotID
+}
+
+// Restore 恢复配置快照 (与重新加载配置相同: 校验通过后替换, 应用运行模式并通知配置变更)
+// 快照校验失败时 (如应用目录已删除) 返回错误, 当前配置保持不变
+func Restore(id int) error {
+	snapshotMutex.Lock()
+	index := -1
+	for i, snap := range snapshots {
+		if snap.id == id {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		snapshotMutex.Unlock()
+		return fmt.Errorf("config snapshot %d not found", id)
+	}
+
+	// 标记为最近使用
+	snap := snapshots[index]
+	snapshots = append(snapshots[:index], snapshots[index+1:]...)
+	snapshots = append(snapshots, snap)
+	snapshotMutex.Unlock()
+
+	cfg := snap.conf.clone()
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("config snapshot %d is no longer valid: %s", id, err.Error())
+	}
+	swapConf(cfg, "restore", fmt.Sprintf("snapshot: %d", id))
+	return nil
+}
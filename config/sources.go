@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/yaoapp/kun/log"
+)
+
+// Source 配置来源 (返回部分配置, 格式为环境变量名称 => 值)
+type Source interface {
+	Name() string
+	Vars() (map[string]string, error)
+}
+
+// fileSource .env 配置文件
+type fileSource string
+
+// urlSource 远程 .env 格式配置
+type urlSource string
+
+// funcSource 自定义配置来源
+type funcSource struct {
+	name string
+	fn   func() (map[string]string, error)
+}
+
+// FileSource 从 .env 配置文件读取配置
+func FileSource(path string) Source { return fileSource(path) }
+
+// URLSource 从远程地址读取 .env 格式配置 (超时时间及最大字节数与 LoadFromURL 相同)
+func URLSource(url string) Source { return urlSource(url) }
+
+// ProviderSource 从自定义来源 (如配置中心) 读取配置
+func ProviderSource(name string, fn func() (map[string]string, error)) Source {
+	return funcSource{name: name, fn: fn}
+}
+
+func (s fileSource) Name() string { return "file " + string(s) }
+func (s fileSource) Vars() (map[string]string, error) {
+	return godotenv.Read(string(s))
+}
+
+func (s urlSource) Name() string { return "url " + string(s) }
+func (s urlSource) Vars() (map[string]string, error) {
+	data, err := fetchConfig(string(s))
+	if err != nil {
+		return nil, err
+	}
+	return godotenv.Unmarshal(string(data))
+}
+
+func (s funcSource) Name() string                     { return s.name }
+func (s funcSource) Vars() (map[string]string, error) { return s.fn() }
+
+// ReloadMerged 重新读取全部配置来源, 按顺序合并 (靠后的来源优先, 均覆盖系统环境变量) 并校验后替换当前配置
+// 任一来源读取失败或校验失败时保留当前配置; 不修改系统环境变量
+func ReloadMerged(sources ...Source) error {
+	markLoaded()
+	vars := environ()
+	names := []string{}
+	for _, source := range sources {
+		values, err := source.Vars()
+		if err != nil {
+			log.Error("config reload rejected, keep the current config: %s: %s", source.Name(), err.Error())
+			return fmt.Errorf("%s: %s", source.Name(), err.Error())
+		}
+		for key, value := range applyEnvPrefix(values) {
+			vars[key] = value
+		}
+		names = append(names, source.Name())
+	}
+
+	cfg, err := parse(vars)
+	if err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Error("config reload rejected, keep the current config: %s", err.Error())
+		return err
+	}
+
+	swapConf(cfg, "reload", "sources: "+strings.Join(names, ", "))
+	return nil
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// StaticMount 静态文件目录挂载
+type StaticMount struct {
+	Prefix string // 访问路径前缀 (例: /assets)
+	Dir    string // 文件目录 (绝对路径)
+}
+
+// reservedPrefixes 引擎使用的路径前缀, 不能挂载静态文件目录
+var reservedPrefixes = []string{"/api", "/websocket", "/xiang"}
+
+// StaticMounts 解析 YAO_STATIC_MOUNTS (例: /assets=./public,/docs=./documentation)
+// 相对路径按应用目录解析, 目录必须存在且位于应用目录内 (IsUnderRoot)
+func (c Config) StaticMounts() ([]StaticMount, error) {
+	mounts := []StaticMount{}
+	seen := map[string]bool{}
+	errs := Errors{}
+	for _, pair := range strings.Split(c.Mounts, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[1]) == "" {
+			errs = append(errs, fmt.Errorf("YAO_STATIC_MOUNTS: invalid mount %q (format: /prefix=dir)", pair))
+			continue
+		}
+		prefix, dir := strings.TrimRight(strings.TrimSpace(kv[0]), "/"), strings.TrimSpace(kv[1])
+		if !strings.HasPrefix(prefix, "/") {
+			errs = append(errs, fmt.Errorf("YAO_STATIC_MOUNTS: prefix of %q must start with /", pair))
+			continue
+		}
+		if reservedPrefix(prefix) {
+			errs = append(errs, fmt.Errorf("YAO_STATIC_MOUNTS: prefix %s is reserved (%s)", prefix, strings.Join(reservedPrefixes, ", ")))
+			continue
+		}
+		if seen[prefix] {
+			errs = append(errs, fmt.Errorf("YAO_STATIC_MOUNTS: prefix %s is mounted more than once", prefix))
+			continue
+		}
+		seen[prefix] = true
+
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(c.Root, dir)
+		}
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("YAO_STATIC_MOUNTS: %s: %s", prefix, err.Error()))
+			continue
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			errs = append(errs, fmt.Errorf("YAO_STATIC_MOUNTS: %s: %s is not a directory", prefix, dir))
+			continue
+		}
+		if under, _ := c.IsUnderRoot(dir); !under {
+			errs = append(errs, fmt.Errorf("YAO_STATIC_MOUNTS: %s: %s is outside the application directory", prefix, dir))
+			continue
+		}
+		mounts = append(mounts, StaticMount{Prefix: prefix, Dir: dir})
+	}
+
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	return mounts, nil
+}
+
+// reservedPrefix 路径前缀是否与引擎使用的路径冲突
+func reservedPrefix(prefix string) bool {
+	for _, reserved := range reservedPrefixes {
+		if prefix == reserved || strings.HasPrefix(prefix, reserved+"/") {
+			return true
+		}
+	}
+	return prefix == ""
+}
+
+// validateStaticMounts 检查静态文件目录挂载
+func (c Config) validateStaticMounts() error {
+	_, err := c.StaticMounts()
+	return err
+}
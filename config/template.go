@@ -0,0 +1,99 @@
+package config
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"strings"
+)
+
+// sources 配置结构体定义源码 (用于读取配置项说明)
+//
+//go:embed types.go config.go
+var sources embed.FS
+
+// templateGroups 配置模板分组名称 (结构体名称 => 分组名称)
+var templateGroups = map[string]string{
+	"Config":        "General",
+	"ServiceConfig": "Service",
+	"DBConfig":      "Database",
+	"SessionConfig": "Session",
+}
+
+// EnvTemplate 输出 .env 配置模板 (按分组输出全部配置项, 每项为注释掉的默认值及说明, 敏感信息单独标注)
+func EnvTemplate(w io.Writer) error {
+	docs, err := fieldDocs()
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintln(out, "# Yao App Engine configuration")
+	fmt.Fprintln(out, "# Uncomment and edit the settings you want to change")
+
+	group := ""
+	for _, field := range (&Config{}).fields() {
+		if name := templateGroup(field.Struct); name != group {
+			group = name
+			fmt.Fprintf(out, "\n# ---- %s ----\n", group)
+		}
+
+		line := fmt.Sprintf("# %s=%s", envVarName(field.Env), field.Default)
+		if doc := docs[field.Struct+"."+field.Field.Name]; doc != "" {
+			line += "  # " + doc
+		}
+		if field.secret() {
+			line += " [SECRET]"
+		}
+		fmt.Fprintln(out, line)
+	}
+	return out.Flush()
+}
+
+func templateGroup(name string) string {
+	if group, has := templateGroups[name]; has {
+		return group
+	}
+	return strings.TrimSuffix(name, "Config")
+}
+
+// fieldDocs 读取配置项说明 (结构体名称.字段名称 => 行尾注释)
+func fieldDocs() (map[string]string, error) {
+	docs := map[string]string{}
+	fset := token.NewFileSet()
+	for _, name := range []string{"types.go", "config.go"} {
+		src, err := sources.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		file, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+
+		ast.Inspect(file, func(node ast.Node) bool {
+			spec, ok := node.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			st, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				return false
+			}
+			for _, field := range st.Fields.List {
+				if field.Comment == nil {
+					continue
+				}
+				for _, ident := range field.Names {
+					docs[spec.Name.Name+"."+ident.Name] = strings.TrimSpace(field.Comment.Text())
+				}
+			}
+			return false
+		})
+	}
+	return docs, nil
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RouteTimeouts 按路径前缀设置的请求超时时间 (格式 /prefix=duration,/prefix2=duration2, 按配置顺序保存)
+type RouteTimeouts []RouteTimeoutRule
+
+// RouteTimeoutRule 路径前缀的请求超时时间
+type RouteTimeoutRule struct {
+	Prefix  string
+	Timeout time.Duration
+}
+
+// UnmarshalText 解析请求超时时间 (例: /reports=120s,/export=300s)
+func (rules *RouteTimeouts) UnmarshalText(text []byte) error {
+	values := RouteTimeouts{}
+	for _, pair := range strings.Split(string(text), ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || !strings.HasPrefix(strings.TrimSpace(kv[0]), "/") {
+			return fmt.Errorf("invalid route timeout %q (format: /prefix=duration)", pair)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(kv[1]))
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid route timeout %q (the duration must be positive, example: /reports=120s)", pair)
+		}
+		values = append(values, RouteTimeoutRule{Prefix: strings.TrimSpace(kv[0]), Timeout: timeout})
+	}
+	*rules = values
+	return nil
+}
+
+// MarshalText 输出请求超时时间
+func (rules RouteTimeouts) MarshalText() ([]byte, error) {
+	pairs := []string{}
+	for _, rule := range rules {
+		pairs = append(pairs, rule.Prefix+"="+rule.Timeout.String())
+	}
+	return []byte(strings.Join(pairs, ",")), nil
+}
+
+// RouteTimeout 请求路径的超时时间 (使用最长匹配的 YAO_ROUTE_TIMEOUTS 前缀, 未匹配时为 YAO_REQUEST_TIMEOUT, 0 不限制)
+func (s ServiceConfig) RouteTimeout(path string) time.Duration {
+	timeout, matched := s.RequestTimeout, -1
+	for _, rule := range s.RouteTimeouts {
+		if strings.HasPrefix(path, rule.Prefix) && len(rule.Prefix) > matched {
+			timeout, matched = rule.Timeout, len(rule.Prefix)
+		}
+	}
+	return timeout
+}
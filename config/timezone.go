@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yaoapp/kun/log"
+)
+
+// checkTimezone 输出系统时区, 与预期时区 (YAO_LOG_EXPECTED_TZ) 不符时输出警告
+// 预期时区可以是 UTC, local (非 UTC 的本地时区) 或时区名称 (如 Asia/Shanghai)
+func checkTimezone(cfg Config) {
+	now := currentClock().Now()
+	name, offset := now.Zone()
+	log.With(log.F{"zone": name, "offset": offset, "location": time.Local.String()}).Trace("log timezone")
+
+	expected := strings.TrimSpace(cfg.LogExpectedTZ)
+	switch strings.ToLower(expected) {
+	case "":
+		return
+
+	case "utc":
+		if offset != 0 {
+			log.Warn("Log timestamps use the local timezone %s (%s), but YAO_LOG_EXPECTED_TZ is UTC. Set TZ=UTC to fix it", name, now.Format("-07:00"))
+		}
+
+	case "local":
+		if offset == 0 {
+			log.Warn("Log timestamps use UTC, but YAO_LOG_EXPECTED_TZ is local. Check the TZ environment variable or /etc/localtime")
+		}
+
+	default:
+		location, err := time.LoadLocation(expected)
+		if err != nil {
+			log.Warn("YAO_LOG_EXPECTED_TZ %s is not a valid timezone. %s", expected, err.Error())
+			return
+		}
+		_, expectedOffset := now.In(location).Zone()
+		if expectedOffset != offset {
+			log.Warn("Log timestamps use the timezone %s (%s), but YAO_LOG_EXPECTED_TZ is %s", name, now.Format("-07:00"), expected)
+		}
+	}
+}
+
+// validateTimezone 检查配置的时区名称能否加载 (精简镜像中可能缺少时区数据库, 此时时间会按 UTC 处理)
+func (c Config) validateTimezone() error {
+	name := strings.TrimSpace(c.LogExpectedTZ)
+	switch strings.ToLower(name) {
+	case "", "utc", "local":
+		return nil
+	}
+
+	if _, err := time.LoadLocation(name); err != nil {
+		if !tzdataAvailable() {
+			return fmt.Errorf("YAO_LOG_EXPECTED_TZ %s: timezone database not found; add tzdata to the image or embed time/tzdata", name)
+		}
+		return fmt.Errorf("YAO_LOG_EXPECTED_TZ %s is not a valid timezone. %s", name, err.Error())
+	}
+	return nil
+}
+
+// tzdataAvailable 时区数据库是否可用
+func tzdataAvailable() bool {
+	_, err := time.LoadLocation("America/New_York")
+	return err == nil
+}
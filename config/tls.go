@@ -0,0 +1,159 @@
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/yaoapp/kun/log"
+)
+
+// TLSVersion TLS 协议版本
+type TLSVersion uint16
+
+// CipherSuites TLS 加密套件列表
+type CipherSuites []uint16
+
+// certCache 当前 HTTPS 证书 (*tls.Certificate)
+var certCache atomic.Value
+
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// UnmarshalText 解析 TLS 版本 1.2|1.3
+func (v *TLSVersion) UnmarshalText(text []byte) error {
+	name := strings.TrimSpace(string(text))
+	if name == "" {
+		*v = TLSVersion(tls.VersionTLS12)
+		return nil
+	}
+	version, has := tlsVersions[name]
+	if !has {
+		return fmt.Errorf("unsupported TLS version %q (supported: 1.2, 1.3)", name)
+	}
+	*v = TLSVersion(version)
+	return nil
+}
+
+// MarshalText 输出 TLS 版本名称
+func (v TLSVersion) MarshalText() ([]byte, error) {
+	for name, version := range tlsVersions {
+		if uint16(v) == version {
+			return []byte(name), nil
+		}
+	}
+	return []byte{}, nil
+}
+
+// UnmarshalText 解析加密套件名称列表 (使用 | 分隔)
+func (suites *CipherSuites) UnmarshalText(text []byte) error {
+	ids := CipherSuites{}
+	for _, name := range strings.Split(string(text), "|") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		suite, err := cipherSuiteByName(name)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, suite.ID)
+	}
+	*suites = ids
+	return nil
+}
+
+// MarshalText 输出加密套件名称列表
+func (suites CipherSuites) MarshalText() ([]byte, error) {
+	names := []string{}
+	for _, id := range suites {
+		names = append(names, tls.CipherSuiteName(id))
+	}
+	return []byte(strings.Join(names, "|")), nil
+}
+
+// TLSConfig 根据证书配置生成 HTTPS TLS 配置 (未设置证书返回 nil)
+func (s ServiceConfig) TLSConfig() (*tls.Config, error) {
+	if s.Cert == "" && s.Key == "" {
+		return nil, nil
+	}
+
+	if err := s.ReloadCert(); err != nil {
+		return nil, err
+	}
+
+	minVersion := uint16(s.TLSMinVersion)
+	if minVersion == 0 {
+		minVersion = tls.VersionTLS12
+	}
+
+	// 通过 GetCertificate 读取证书, 重新加载证书后新连接使用新证书
+	cfg := &tls.Config{
+		GetCertificate: s.GetCertificate,
+		MinVersion:     minVersion,
+	}
+
+	// 未指定加密套件时, 使用 Go 默认的安全套件
+	if len(s.TLSCipherSuites) > 0 {
+		cfg.CipherSuites = []uint16(s.TLSCipherSuites)
+	}
+	return cfg, nil
+}
+
+// GetCertificate 读取当前 HTTPS 证书 (用于 tls.Config.GetCertificate, 未加载时从证书文件加载)
+func (s *ServiceConfig) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if cert, ok := certCache.Load().(*tls.Certificate); ok {
+		return cert, nil
+	}
+	if err := s.ReloadCert(); err != nil {
+		return nil, err
+	}
+	return certCache.Load().(*tls.Certificate), nil
+}
+
+// ReloadCert 重新加载证书文件并替换当前证书 (加载失败时保留当前证书, 已建立的连接不受影响)
+func (s *ServiceConfig) ReloadCert() error {
+	cert, err := tls.LoadX509KeyPair(s.Cert, s.Key)
+	if err != nil {
+		return err
+	}
+	certCache.Store(&cert)
+	return nil
+}
+
+// reloadCert 配置重新加载时更新证书 (仅在已加载证书时)
+func reloadCert(cfg Config) {
+	if _, ok := certCache.Load().(*tls.Certificate); !ok || !cfg.HTTPS() {
+		return
+	}
+	if err := cfg.ReloadCert(); err != nil {
+		log.With(log.F{"cert": cfg.Cert, "key": cfg.Key}).Error("reload certificate failed, keep the current one. %s", err.Error())
+		return
+	}
+	log.With(log.F{"cert": cfg.Cert}).Info("certificate reloaded")
+}
+
+// cipherSuiteByName 按名称查找可配置的加密套件
+// 不安全的加密套件及 TLS 1.3 加密套件 (Go 不支持配置 TLS 1.3 加密套件) 返回错误
+func cipherSuiteByName(name string) (*tls.CipherSuite, error) {
+	for _, suite := range tls.InsecureCipherSuites() {
+		if suite.Name == name {
+			return nil, fmt.Errorf("TLS cipher suite %q is insecure", name)
+		}
+	}
+	for _, suite := range tls.CipherSuites() {
+		if suite.Name != name {
+			continue
+		}
+		for _, version := range suite.SupportedVersions {
+			if version != tls.VersionTLS13 {
+				return suite, nil
+			}
+		}
+		return nil, fmt.Errorf("TLS cipher suite %q is a TLS 1.3 cipher suite, which is not configurable", name)
+	}
+	return nil, fmt.Errorf("unknown TLS cipher suite %q", name)
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloadCert(t *testing.T) {
+	dir := t.TempDir()
+	s := ServiceConfig{Cert: filepath.Join(dir, "cert.pem"), Key: filepath.Join(dir, "key.pem")}
+
+	writeTestCert(t, s.Cert, s.Key, "first")
+	cfg, err := s.TLSConfig()
+	assert.Nil(t, err)
+
+	cert, err := cfg.GetCertificate(&tls.ClientHelloInfo{})
+	assert.Nil(t, err)
+	assert.Equal(t, "first", certCommonName(t, cert))
+
+	writeTestCert(t, s.Cert, s.Key, "second")
+	reloadCert(Config{ServiceConfig: s})
+	cert, err = cfg.GetCertificate(&tls.ClientHelloInfo{})
+	assert.Nil(t, err)
+	assert.Equal(t, "second", certCommonName(t, cert))
+
+	// 加载失败时保留当前证书
+	os.WriteFile(s.Cert, []byte("broken"), 0644)
+	assert.Error(t, s.ReloadCert())
+	cert, err = cfg.GetCertificate(&tls.ClientHelloInfo{})
+	assert.Nil(t, err)
+	assert.Equal(t, "second", certCommonName(t, cert))
+}
+
+func writeTestCert(t *testing.T, certFile, keyFile, name string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+}
+
+func certCommonName(t *testing.T, cert *tls.Certificate) string {
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func TestCheckTLS(t *testing.T) {
+	dir := t.TempDir()
+	cfg := Config{ClockSkew: 30 * time.Second}
+	cfg.Cert, cfg.Key = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeTestCert(t, cfg.Cert, cfg.Key, "skew")
+	assert.Nil(t, cfg.ReloadCert())
+	assert.Nil(t, cfg.CheckTLS())
+
+	// 证书有效期为前后 1 小时
+	defer SetClock(nil)
+	SetClock(fakeClock{now: time.Now().Add(time.Hour + 10*time.Second)})
+	assert.Nil(t, cfg.CheckTLS())
+
+	SetClock(fakeClock{now: time.Now().Add(time.Hour + time.Minute)})
+	assert.Contains(t, cfg.CheckTLS().Error(), "expired at")
+
+	SetClock(fakeClock{now: time.Now().Add(-time.Hour - time.Minute)})
+	assert.Contains(t, cfg.CheckTLS().Error(), "not valid before")
+
+	cfg.ClockSkew = -time.Second
+	assert.Error(t, cfg.validateClockSkew())
+}
+
+func TestCertIssues(t *testing.T) {
+	dir := t.TempDir()
+	cfg := Config{Mode: "production"}
+	cfg.Cert, cfg.Key = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeTestCert(t, cfg.Cert, cfg.Key, "self-signed")
+	assert.Nil(t, cfg.ReloadCert())
+	assert.Contains(t, cfg.certIssues().Error(), "is self-signed")
+
+	cfg.Mode = "development"
+	assert.Nil(t, cfg.certIssues())
+}
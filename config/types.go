@@ -1,25 +1,151 @@
 package config
 
+import "time"
+
 // Config 象传应用引擎配置
 type Config struct {
-	Mode    string `json:"mode,omitempty" env:"YAO_ENV" envDefault:"production"`    // 象传引擎启动模式 production/development
-	Root    string `json:"root,omitempty" env:"YAO_ROOT" envDefault:"."`            // 应用根目录
-	Host    string `json:"host,omitempty" env:"YAO_HOST" envDefault:"0.0.0.0"`      // 服务监听地址
-	Port    int    `json:"port,omitempty" env:"YAO_PORT" envDefault:"5099"`         // 服务监听端口
-	Cert    string `json:"cert,omitempty" env:"YAO_CERT"`                           // HTTPS 证书文件地址
-	Key     string `json:"key,omitempty" env:"YAO_KEY"`                             // HTTPS 证书密钥地址
+	Mode string `json:"mode,omitempty" env:"YAO_ENV" envDefault:"production"` // 象传引擎启动模式 production/development
+	Root string `json:"root,omitempty" env:"YAO_ROOT" envDefault:"."`         // 应用根目录
+	ServiceConfig
 	Log     string `json:"log,omitempty" env:"YAO_LOG"`                             // 服务日志地址
 	LogMode string `json:"log_mode,omitempty" env:"YAO_LOG_MODE" envDefault:"TEXT"` // 服务日志模式 JSON|TEXT
+
+	LogFallbacks []string `json:"log_fallbacks,omitempty" env:"YAO_LOG_FALLBACKS" envSeparator:"|"` // 备用日志地址 (YAO_LOG 不可用时依次尝试)
+
+	LogFieldDenylist []string  `json:"log_field_denylist,omitempty" env:"YAO_LOG_FIELD_DENYLIST" envSeparator:"|"` // 禁止写入日志的字段
+	LogFieldRedact   string    `json:"log_field_redact,omitempty" env:"YAO_LOG_FIELD_REDACT" envDefault:"strip"`   // 禁止字段处理方式 strip|hash
+	LogDefaultFields LogFields `json:"log_fields,omitempty" env:"YAO_LOG_FIELDS"`                                  // 每条日志附加的字段 key=value,key2=value2
+	LogExpectedTZ    string    `json:"log_expected_tz,omitempty" env:"YAO_LOG_EXPECTED_TZ"`                        // 日志预期时区 UTC|local|时区名称
+
+	LogTimeFormat string `json:"log_time_format,omitempty" env:"YAO_LOG_TIME_FORMAT"` // 日志时间格式 (Go 时间格式, 如 2006-01-02T15:04:05.000Z07:00, 为空时保持原格式)
+	LogTimeUTC    bool   `json:"log_time_utc,omitempty" env:"YAO_LOG_TIME_UTC"`       // 日志时间是否转换为 UTC
+
+	LogMaxSize    int `json:"log_max_size,omitempty" env:"YAO_LOG_MAX_SIZE" envDefault:"0"`       // 日志文件轮转大小 (MB, 0 不轮转)
+	LogMaxBackups int `json:"log_max_backups,omitempty" env:"YAO_LOG_MAX_BACKUPS" envDefault:"0"` // 轮转后保留的日志文件数量 (0 不限制)
+	LogMaxAge     int `json:"log_max_age,omitempty" env:"YAO_LOG_MAX_AGE" envDefault:"0"`         // 轮转后的日志文件保留天数 (0 不限制)
+
+	LogFieldMap LogFields `json:"log_field_map,omitempty" env:"YAO_LOG_FIELD_MAP"` // 日志字段重命名 (例: level=severity,msg=message, 未列出的字段保持不变)
+
+	LogPipeReconnect bool `json:"log_pipe_reconnect,omitempty" env:"YAO_LOG_PIPE_RECONNECT" envDefault:"false"` // 日志地址为命名管道时, 读取端关闭后定时重新打开管道 (断开期间写入 stderr)
+
 	// Session   string        `json:"session,omitempty" env:"YAO_SESSION" envDefault:"memory"`         // 用户会话模式 memory|redis|database
-	JWTSecret string        `json:"jwt_secret,omitempty" env:"YAO_JWT_SECRET"` // JWT 密钥
-	DB        DBConfig      `json:"db,omitempty"`                              // 数据库配置
+	JWTSecret string        `json:"jwt_secret,omitempty" env:"YAO_JWT_SECRET" secret:"true"` // JWT 密钥
+	DB        DBConfig      `json:"db,omitempty"`                                            // 数据库配置
 	Session   SessionConfig `json:"session,omitempty"`
+
+	SecretsDir string `json:"secrets_dir,omitempty" env:"YAO_SECRETS_DIR" envDefault:"/run/secrets"` // 密钥文件目录 (生产环境自动读取, 文件名为环境变量名称)
+
+	RedactFields []string `json:"redact_fields,omitempty" env:"YAO_REDACT_FIELDS" envSeparator:"|"` // 配置导出时额外隐藏的配置项 (环境变量名称, 例: YAO_DB_PRIMARY|YAO_PUBLIC_HOST)
+
+	PluginEnvAllowlist []string `json:"plugin_env_allowlist,omitempty" env:"YAO_PLUGIN_ENV_ALLOWLIST" envSeparator:"|"` // 插件可读取的配置项及系统环境变量 (例: YAO_ENV|YAO_DB_AESKEY|PATH, 未设置时传递全部非敏感配置)
+
+	Modules        []string `json:"modules,omitempty" env:"YAO_MODULES" envSeparator:"|"`                 // 启用的子系统模块 (为空启用全部)
+	DisableModules []string `json:"disable_modules,omitempty" env:"YAO_DISABLE_MODULES" envSeparator:"|"` // 禁用的子系统模块
+
+	ModuleCreateDirs []string `json:"module_create_dirs,omitempty" env:"YAO_MODULE_CREATE_DIRS" envSeparator:"|"` // 目录不存在时自动创建的模块 (目录可选的模块)
+
+	DataRetention       time.Duration `json:"data_retention,omitempty" env:"YAO_DATA_RETENTION"`                               // 临时数据保留时长 (0 不清理)
+	DataCleanupInterval time.Duration `json:"data_cleanup_interval,omitempty" env:"YAO_DATA_CLEANUP_INTERVAL" envDefault:"1h"` // 临时数据清理间隔
+
+	AllowedUploadTypes MIMETypes `json:"allowed_upload_types,omitempty" env:"YAO_ALLOWED_UPLOAD_TYPES"` // 允许上传的文件类型 (| 分隔, 支持 image/*, 为空不限制)
+
+	Strict         bool   `json:"strict,omitempty" env:"YAO_STRICT" envDefault:"false"`           // 严格模式 (配置警告视为错误)
+	AuditLog       string `json:"audit_log,omitempty" env:"YAO_AUDIT_LOG"`                        // 审计日志文件 (记录配置变更, 为空时写入服务日志)
+	ConfigDumpPath string `json:"config_dump_path,omitempty" env:"YAO_CONFIG_DUMP_PATH"`          // 生效配置输出文件 (JSON, 敏感信息已隐藏)
+	Maintenance    bool   `json:"maintenance,omitempty" env:"YAO_MAINTENANCE" envDefault:"false"` // 维护模式 (可通过 SetMaintenance 在运行时切换, 请使用 InMaintenance 读取)
+	Workers        int    `json:"workers,omitempty" env:"YAO_WORKERS" envDefault:"0"`             // 工作协程数量 (0 使用 GOMAXPROCS)
+
+	KillSwitches KillSwitches `json:"kill_switches,omitempty" env:"YAO_KILL"` // 限时关闭的功能 (例: feature_a@2024-01-02T15:04:05Z, 到期后自动恢复)
+
+	ConfigFetchTimeout time.Duration `json:"config_fetch_timeout,omitempty" env:"YAO_CONFIG_FETCH_TIMEOUT" envDefault:"10s"` // 远程读取配置超时时间
+	ConfigMaxSize      ByteSize      `json:"config_max_size,omitempty" env:"YAO_CONFIG_MAX_SIZE" envDefault:"1MB"`           // 远程配置最大字节数
+
+	HealthChecks HealthChecks `json:"health_checks,omitempty" env:"YAO_HEALTH_CHECKS"` // 健康检查项 (例: db|session|url:https://dep/health)
+
+	Version   string `json:"version,omitempty" env:"YAO_VERSION"`       // 程序版本 (未设置时使用编译时注入的版本)
+	Commit    string `json:"commit,omitempty" env:"YAO_COMMIT"`         // 程序提交版本
+	BuildTime string `json:"build_time,omitempty" env:"YAO_BUILD_TIME"` // 程序编译时间
+
+	ReloadStrict bool `json:"reload_strict,omitempty" env:"YAO_RELOAD_STRICT" envDefault:"true"` // 重新加载配置校验失败时拒绝替换 (false 输出警告后替换)
+
+	ReloadInterval time.Duration `json:"reload_interval,omitempty" env:"YAO_RELOAD_INTERVAL" envDefault:"0"` // 定时检查配置文件并重新加载的间隔 (0 不检查)
+
+	WaitForDB        bool          `json:"wait_for_db,omitempty" env:"YAO_WAIT_FOR_DB" envDefault:"false"`               // 数据库连接成功后再启动服务
+	WaitForDBTimeout time.Duration `json:"wait_for_db_timeout,omitempty" env:"YAO_WAIT_FOR_DB_TIMEOUT" envDefault:"60s"` // 等待数据库的最长时间
+
+	StartupWebhook        string        `json:"startup_webhook,omitempty" env:"YAO_STARTUP_WEBHOOK"`                                 // 启动及配置变更事件通知地址 (POST JSON)
+	StartupWebhookTimeout time.Duration `json:"startup_webhook_timeout,omitempty" env:"YAO_STARTUP_WEBHOOK_TIMEOUT" envDefault:"5s"` // 事件通知超时时间
+
+	ClockSkew time.Duration `json:"clock_skew,omitempty" env:"YAO_CLOCK_SKEW" envDefault:"30s"` // 允许的时钟偏差 (JWT 及证书有效期检查两端各放宽该时长)
+
+	Retry RetryConfig `json:"retry,omitempty"` // 重试配置
+}
+
+// RetryConfig 重试配置 (指数退避)
+type RetryConfig struct {
+	Initial    time.Duration `json:"initial,omitempty" env:"YAO_RETRY_INITIAL" envDefault:"500ms"`   // 首次重试等待时间
+	Max        time.Duration `json:"max,omitempty" env:"YAO_RETRY_MAX" envDefault:"10s"`             // 最长等待时间
+	Multiplier float64       `json:"multiplier,omitempty" env:"YAO_RETRY_MULTIPLIER" envDefault:"2"` // 每次重试等待时间倍数
+}
+
+// ServiceConfig 服务配置
+type ServiceConfig struct {
+	Host            string       `json:"host,omitempty" env:"YAO_HOST" envDefault:"0.0.0.0"`                   // 服务监听地址
+	Port            int          `json:"port,omitempty" env:"YAO_PORT" envDefault:"5099"`                      // 服务监听端口
+	Cert            string       `json:"cert,omitempty" env:"YAO_CERT"`                                        // HTTPS 证书文件地址
+	Key             string       `json:"key,omitempty" env:"YAO_KEY"`                                          // HTTPS 证书密钥地址
+	TLSMinVersion   TLSVersion   `json:"tls_min_version,omitempty" env:"YAO_TLS_MIN_VERSION" envDefault:"1.2"` // HTTPS 最低 TLS 版本 1.2|1.3
+	TLSCipherSuites CipherSuites `json:"tls_cipher_suites,omitempty" env:"YAO_TLS_CIPHER_SUITES"`              // HTTPS 加密套件 (为空使用 Go 默认安全套件)
+
+	RequestIDHeader        string          `json:"request_id_header,omitempty" env:"YAO_REQUEST_ID_HEADER" envDefault:"X-Request-ID"` // 请求 ID 头名称
+	RequestIDTrustIncoming bool            `json:"request_id_trust_incoming,omitempty" env:"YAO_REQUEST_ID_TRUST_INCOMING"`           // 是否复用请求中携带的请求 ID
+	RequestIDFormat        RequestIDFormat `json:"request_id_format,omitempty" env:"YAO_REQUEST_ID_FORMAT" envDefault:"uuid"`         // 请求 ID 格式 uuid|nanoid
+
+	PublicHost string `json:"public_host,omitempty" env:"YAO_PUBLIC_HOST"` // 对外访问域名 (可包含端口, 与监听地址不同时设置)
+
+	DrainDelay   time.Duration `json:"drain_delay,omitempty" env:"YAO_DRAIN_DELAY"`                      // 关闭服务前就绪检查返回失败的时长 (等待负载均衡摘除流量)
+	DrainTimeout time.Duration `json:"drain_timeout,omitempty" env:"YAO_DRAIN_TIMEOUT" envDefault:"30s"` // 等待处理中请求完成的最长时间
+
+	MaxHeaderSize ByteSize `json:"max_header_bytes,omitempty" env:"YAO_MAX_HEADER_BYTES"` // 请求头最大字节数 (例: 64KiB, 为空使用 Go 默认值 1MiB)
+
+	RecoverStack       bool `json:"recover_stack,omitempty" env:"YAO_RECOVER_STACK"`               // 请求处理 panic 时输出完整调用栈
+	RecoverExposeError bool `json:"recover_expose_error,omitempty" env:"YAO_RECOVER_EXPOSE_ERROR"` // 请求处理 panic 时在 500 响应中返回错误信息 (仅用于开发环境)
+
+	LogBodies         bool     `json:"log_bodies,omitempty" env:"YAO_LOG_BODIES"`                                       // 记录请求及响应内容 (用于排查问题)
+	LogBodiesMaxBytes ByteSize `json:"log_bodies_max_bytes,omitempty" env:"YAO_LOG_BODIES_MAX_BYTES" envDefault:"4KiB"` // 记录内容的最大字节数
+	LogBodiesPaths    []string `json:"log_bodies_paths,omitempty" env:"YAO_LOG_BODIES_PATHS" envSeparator:"|"`          // 记录内容的请求路径前缀 (为空记录全部)
+	AllowBodyLogging  bool     `json:"allow_body_logging,omitempty" env:"YAO_ALLOW_BODY_LOGGING"`                       // 允许在生产环境记录请求及响应内容
+
+	Allow []string `json:"allow,omitempty" env:"YAO_ALLOW" envSeparator:"|"` // 跨域访问域名列表 (例: https://*.example.com|http://localhost:3000)
+
+	ErrorFormat string `json:"error_format,omitempty" env:"YAO_ERROR_FORMAT" envDefault:"legacy"` // 错误响应格式 legacy|simple|problem (problem 为 RFC 7807 problem+json)
+
+	MaxConcurrentRequests int           `json:"max_concurrent_requests,omitempty" env:"YAO_MAX_CONCURRENT_REQUESTS" envDefault:"0"` // 同时处理的最大请求数 (超出返回 503, 0 不限制)
+	ConcurrencyWait       time.Duration `json:"concurrency_wait,omitempty" env:"YAO_CONCURRENCY_WAIT" envDefault:"0"`               // 达到最大请求数时等待空闲的时长 (0 立即返回 503)
+
+	PathPrefix string `json:"base_path,omitempty" env:"YAO_BASE_PATH"` // 服务访问路径前缀 (部署在反向代理子路径时设置, 例: /yao)
+
+	JSONEncoder string `json:"json_encoder,omitempty" env:"YAO_JSON_ENCODER" envDefault:"stdlib"` // 响应 JSON 编码库 stdlib|jsoniter
+
+	RequestTimeout time.Duration `json:"request_timeout,omitempty" env:"YAO_REQUEST_TIMEOUT" envDefault:"0"` // 请求处理截止时间 (0 不限制; 仅设置请求 context 的截止时间, 不检查 context 的处理程序不会被中断, 处理完成后仍未响应时返回 504)
+	RouteTimeouts  RouteTimeouts `json:"route_timeouts,omitempty" env:"YAO_ROUTE_TIMEOUTS"`                  // 按路径前缀覆盖请求超时时间 (例: /reports=120s,/export=300s)
+
+	MultipartMemory ByteSize `json:"multipart_max_memory,omitempty" env:"YAO_MULTIPART_MAX_MEMORY"` // 上传文件时内存缓存的最大字节数 (超出部分写入临时文件, 为空使用 gin 默认值 32MiB)
+
+	Mounts string `json:"static_mounts,omitempty" env:"YAO_STATIC_MOUNTS"` // 静态文件目录挂载 (例: /assets=./public,/docs=./documentation, 目录须位于应用目录内)
+
+	TrailingSlash string `json:"trailing_slash,omitempty" env:"YAO_TRAILING_SLASH" envDefault:"redirect"` // 路径结尾 / 处理方式 redirect|strict|ignore
 }
 
 // DBConfig 数据库配置
 type DBConfig struct {
-	Driver    string   `json:"driver,omitempty" env:"YAO_DB_DRIVER" envDefault:"sqlite3"`                        // 数据库驱动 sqlite3| mysql| postgres
-	Primary   []string `json:"primary,omitempty" env:"YAO_DB_PRIMARY" envSeparator:"|" envDefault:"./db/yao.db"` // 主库连接DSN
-	Secondary []string `json:"secondary,omitempty" env:"YAO_DB_SECONDARY" envSeparator:"|"`                      // 从库连接DSN
-	AESKey    string   `json:"aeskey,omitempty" env:"YAO_DB_AESKEY"`                                             // 加密存储KEY
+	Driver    string   `json:"driver,omitempty" env:"YAO_DB_DRIVER" envDefault:"sqlite3"`                                      // 数据库驱动 sqlite3| mysql| postgres
+	Primary   []string `json:"primary,omitempty" env:"YAO_DB_PRIMARY" envSeparator:"|" envDefault:"./db/yao.db" secret:"true"` // 主库连接DSN (可能包含数据库密码)
+	Secondary []string `json:"secondary,omitempty" env:"YAO_DB_SECONDARY" envSeparator:"|" secret:"true"`                      // 从库连接DSN (可能包含数据库密码)
+	AESKey    string   `json:"aeskey,omitempty" env:"YAO_DB_AESKEY" secret:"true"`                                             // 加密存储KEY
+
+	RequireTLS bool `json:"require_tls,omitempty" env:"YAO_DB_REQUIRE_TLS" envDefault:"false"` // 数据库连接必须使用 TLS
+
+	SQLiteJournalMode string        `json:"sqlite_journal_mode,omitempty" env:"YAO_DB_SQLITE_JOURNAL_MODE"`                 // sqlite3 日志模式 WAL|DELETE|TRUNCATE|PERSIST|MEMORY|OFF (为空不修改数据库文件当前的日志模式, WAL 等模式写入数据库文件后持久生效)
+	SQLiteBusyTimeout time.Duration `json:"sqlite_busy_timeout,omitempty" env:"YAO_DB_SQLITE_BUSY_TIMEOUT" envDefault:"5s"` // sqlite3 数据库锁定时的等待时间
 }
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"mime"
+	"regexp"
+	"strings"
+)
+
+// MIMETypes 文件类型列表 (使用 | 分隔, 支持 type/* 通配)
+type MIMETypes []string
+
+var mimeTypeRe = regexp.MustCompile(`^[a-z0-9][a-z0-9!#$&^_.+\-]*/(\*|[a-z0-9][a-z0-9!#$&^_.+\-]*)$`)
+
+// UnmarshalText 解析文件类型列表
+func (types *MIMETypes) UnmarshalText(text []byte) error {
+	values := MIMETypes{}
+	for _, value := range strings.Split(string(text), "|") {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		if !mimeTypeRe.MatchString(value) {
+			return fmt.Errorf("invalid MIME type %q (format: type/subtype or type/*)", value)
+		}
+		values = append(values, value)
+	}
+	*types = values
+	return nil
+}
+
+// MarshalText 输出文件类型列表
+func (types MIMETypes) MarshalText() ([]byte, error) {
+	return []byte(strings.Join(types, "|")), nil
+}
+
+// UploadTypeAllowed 是否允许上传该类型文件 (未设置 YAO_ALLOWED_UPLOAD_TYPES 时允许全部类型)
+func (c Config) UploadTypeAllowed(mimeType string) bool {
+	if len(c.AllowedUploadTypes) == 0 {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range c.AllowedUploadTypes {
+		if allowed == mediaType {
+			return true
+		}
+		if strings.HasSuffix(allowed, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(allowed, "*")) {
+			return true
+		}
+	}
+	return false
+}
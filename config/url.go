@@ -0,0 +1,68 @@
+package config
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+// HTTPS 是否启用 HTTPS (同时设置证书和密钥)
+func (s ServiceConfig) HTTPS() bool {
+	return s.Cert != "" && s.Key != ""
+}
+
+// Scheme 服务访问协议 http|https
+func (s ServiceConfig) Scheme() string {
+	if s.HTTPS() {
+		return "https"
+	}
+	return "http"
+}
+
+// Addr 服务监听地址 host:port
+func (s ServiceConfig) Addr() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+}
+
+// BaseURL 服务对外访问地址 (优先使用 YAO_PUBLIC_HOST, 省略协议默认端口, 包含 YAO_BASE_PATH, 不含结尾 /)
+func (s ServiceConfig) BaseURL() string {
+	return s.Scheme() + "://" + s.publicHostPort() + s.BasePath()
+}
+
+// BasePath 服务访问路径前缀 (以 / 开头, 不含结尾 /, 未设置返回空字符串)
+func (s ServiceConfig) BasePath() string {
+	prefix := strings.Trim(strings.TrimSpace(s.PathPrefix), "/")
+	if prefix == "" {
+		return ""
+	}
+	return "/" + prefix
+}
+
+// Route 添加路径前缀后的路由地址 (例: /api => /yao/api)
+func (s ServiceConfig) Route(path string) string {
+	return s.BasePath() + "/" + strings.TrimLeft(path, "/")
+}
+
+// publicHostPort 对外访问的 host[:port]
+func (s ServiceConfig) publicHostPort() string {
+	host := s.PublicHost
+	if host != "" {
+		// 对外访问域名已包含端口
+		if _, _, err := net.SplitHostPort(host); err == nil {
+			return host
+		}
+	} else {
+		host = s.Host
+		if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+			host = "127.0.0.1"
+		}
+	}
+
+	if s.Port == 0 || s.HTTPS() && s.Port == 443 || !s.HTTPS() && s.Port == 80 {
+		if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+			return "[" + host + "]"
+		}
+		return host
+	}
+	return net.JoinHostPort(host, strconv.Itoa(s.Port))
+}
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/yaoapp/kun/log"
+)
+
+// Errors 配置校验错误列表
+type Errors []error
+
+// Error 合并输出所有错误
+func (errs Errors) Error() string {
+	messages := []string{}
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Err 没有错误时返回 nil
+func (errs Errors) Err() error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Add 添加错误 (合并展开 Errors, 忽略 nil)
+func (errs *Errors) Add(err error) {
+	if err == nil {
+		return
+	}
+	if list, ok := err.(Errors); ok {
+		*errs = append(*errs, list...)
+		return
+	}
+	*errs = append(*errs, err)
+}
+
+// Validate 校验配置, 返回全部校验错误 (警告输出到日志)
+func (c Config) Validate() error {
+	errors, warnings := c.checks()
+	errs := Errors{}
+	for _, check := range errors {
+		for _, err := range check.issues(c) {
+			if check.custom {
+				err = fmt.Errorf("%s: %s", check.code, err.Error())
+			}
+			errs = append(errs, err)
+		}
+	}
+
+	for _, check := range warnings {
+		for _, err := range check.issues(c) {
+			log.Warn("%s", err.Error())
+		}
+	}
+	return errs.Err()
+}
+
+// validateStatic 仅根据配置值进行的校验 (不读写文件, 不输出日志)
+func (c Config) validateStatic() error {
+	errs := Errors{}
+	for _, check := range staticChecks {
+		errs.Add(check.run(c))
+	}
+	return errs.Err()
+}
+
+// check 配置检查项
+type check struct {
+	code   string             // 检查项名称
+	run    func(Config) error // 返回 nil, 单个错误或 Errors
+	custom bool               // 是否为 RegisterValidator 注册的校验
+}
+
+// issues 执行检查, 返回全部问题
+func (ch check) issues(c Config) []error {
+	return issueList(ch.run(c))
+}
+
+// issueList 展开错误列表 (nil 返回空列表)
+func issueList(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if list, ok := err.(Errors); ok {
+		return list
+	}
+	return []error{err}
+}
+
+// staticChecks 仅根据配置值进行的检查
+var staticChecks = []check{
+	{code: "mode", run: Config.validateMode},
+	{code: "ports", run: Config.validatePorts},
+	{code: "modules", run: Config.validateModules},
+	{code: "retention", run: Config.validateRetention},
+	{code: "db", run: Config.validateDB},
+	{code: "service", run: Config.validateService},
+	{code: "session", run: Config.validateSession},
+	{code: "log", run: Config.ValidateLogConsistency},
+	{code: "log_rotation", run: Config.ValidateRotation},
+	{code: "redact_fields", run: Config.validateRedactFields},
+	{code: "clock_skew", run: Config.validateClockSkew},
+	{code: "webhook", run: Config.validateWebhook},
+}
+
+// checks 全部检查项 (错误及警告)
+// 应用目录, 时区数据库等需要访问文件系统的检查返回错误; 文件系统, JWT 密钥, 未替换变量及证书检查在严格模式 (YAO_STRICT) 下返回错误, 否则为警告
+func (c Config) checks() (errors []check, warnings []check) {
+	errors = append(errors, staticChecks...)
+	errors = append(errors,
+		check{code: "module_roots", run: Config.validateModuleRoots},
+		check{code: "writable", run: Config.validateWritable},
+		check{code: "static_mounts", run: Config.validateStaticMounts},
+		check{code: "timezone", run: Config.validateTimezone},
+	)
+
+	strict := []check{
+		{code: "filesystem", run: Config.filesystemIssues},
+		{code: "jwt_secret", run: Config.jwtSecretIssues},
+		{code: "placeholder", run: Config.placeholderIssues},
+		{code: "cert", run: Config.certIssues},
+	}
+	if c.Strict {
+		errors = append(errors, strict...)
+	} else {
+		warnings = append(warnings, strict...)
+	}
+	warnings = append(warnings,
+		check{code: "db", run: Config.dbWarnings},
+		check{code: "service", run: Config.serviceWarnings},
+		check{code: "session", run: Config.sessionWarnings},
+		check{code: "tls", run: Config.tlsWarnings},
+	)
+
+	// 自定义校验 (在内建校验之后按注册顺序执行)
+	validatorsMutex.Lock()
+	for _, v := range validators {
+		errors = append(errors, check{code: v.name, run: v.fn, custom: true})
+	}
+	validatorsMutex.Unlock()
+	return errors, warnings
+}
+
+// validator 自定义配置校验
+type validator struct {
+	name string
+	fn   func(Config) error
+}
+
+var validators = []validator{}
+var validatorsMutex sync.Mutex
+
+// RegisterValidator 注册自定义配置校验 (在内建校验之后按注册顺序执行, 错误信息以名称开头)
+// 重复注册同名校验时替换原有校验
+func RegisterValidator(name string, fn func(cfg Config) error) {
+	validatorsMutex.Lock()
+	defer validatorsMutex.Unlock()
+	for i, v := range validators {
+		if v.name == name {
+			validators[i].fn = fn
+			return
+		}
+	}
+	validators = append(validators, validator{name: name, fn: fn})
+}
+
+// modes 支持的运行模式
+var modes = []string{"production", "development", "test"}
+
+// validateMode 检查运行模式
+func (c Config) validateMode() error {
+	if !contains(modes, c.Mode) {
+		return fmt.Errorf("YAO_ENV must be one of %s (got %q)", strings.Join(modes, ", "), c.Mode)
+	}
+	return nil
+}
+
+// validatePorts 检查服务端口及会话服务端口
+func (c Config) validatePorts() error {
+	errs := Errors{}
+	if c.Port < 1 || c.Port > 65535 {
+		errs = append(errs, fmt.Errorf("YAO_PORT must be between 1 and 65535 (got %d)", c.Port))
+	}
+	if c.Session.Port < 1 || c.Session.Port > 65535 {
+		errs = append(errs, fmt.Errorf("XIANG_SESSION_PORT must be between 1 and 65535 (got %d)", c.Session.Port))
+	}
+	return errs.Err()
+}
@@ -0,0 +1,37 @@
+package config
+
+// 编译时注入的版本信息
+// go build -ldflags "-X github.com/yaoapp/yao/config.version=0.9.2 -X github.com/yaoapp/yao/config.commit=$(git rev-parse --short HEAD) -X github.com/yaoapp/yao/config.buildTime=$(date -u +%FT%TZ)"
+var (
+	version   string
+	commit    string
+	buildTime string
+)
+
+// applyBuildInfo 未通过环境变量设置版本信息时, 使用编译时注入的版本信息
+func (c *Config) applyBuildInfo() {
+	if c.Version == "" {
+		c.Version = version
+	}
+	if c.Commit == "" {
+		c.Commit = commit
+	}
+	if c.BuildTime == "" {
+		c.BuildTime = buildTime
+	}
+}
+
+// VersionInfo 程序版本信息 (仅包含已设置的字段, 健康检查 /health 返回)
+func (c Config) VersionInfo() map[string]string {
+	info := map[string]string{}
+	if c.Version != "" {
+		info["version"] = c.Version
+	}
+	if c.Commit != "" {
+		info["commit"] = c.Commit
+	}
+	if c.BuildTime != "" {
+		info["build_time"] = c.BuildTime
+	}
+	return info
+}
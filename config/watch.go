@@ -0,0 +1,90 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/yaoapp/kun/log"
+)
+
+// Watch 监听已加载的配置文件 (及 .env.local 等叠加的配置文件), 文件修改后重新加载配置, ctx 取消后退出
+// 连续写入时等待 reloadDebounce 后只重新加载一次; 重新加载后按新的 YAO_ENV 重新设定运行环境, 需要重启才能生效的配置项输出警告
+// 监听配置文件所在目录, 编辑器以重命名方式保存文件时同样生效; 网络文件系统请使用 PollReload
+func Watch(ctx context.Context) error {
+	if envFile == "" {
+		return fmt.Errorf("no env file is loaded")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	files := map[string]bool{envFile: true}
+	for _, overlay := range envOverlays {
+		files[overlay] = true
+	}
+	dirs := map[string]bool{}
+	for file := range files {
+		dir := filepath.Dir(file)
+		if dirs[dir] {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			return err
+		}
+		dirs[dir] = true
+	}
+
+	watchEnvFiles(ctx, files, watcher.Events, watcher.Errors)
+	return nil
+}
+
+// watchEnvFiles 处理配置文件变更事件, 最后一次变更 reloadDebounce 后重新加载配置
+// 配置文件的变更与 PollReload 共用修改时间记录 (reloadIfChanged), 同一修改只重新加载一次
+func watchEnvFiles(ctx context.Context, files map[string]bool, events <-chan fsnotify.Event, errors <-chan error) {
+	var pending <-chan time.Time
+	overlayChanged := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if !files[filepath.Clean(event.Name)] || event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			if filepath.Clean(event.Name) != envFile {
+				overlayChanged = true
+			}
+			pending = currentClock().After(reloadDebounce)
+
+		case err, ok := <-errors:
+			if !ok {
+				return
+			}
+			log.Error("config watcher: %s", err.Error())
+
+		case <-pending:
+			pending = nil
+			var err error
+			if overlayChanged {
+				overlayChanged = false
+				err = Reload()
+				recordEnvModTime(envFile)
+			} else {
+				_, err = reloadIfChanged()
+			}
+			if err != nil {
+				log.Error("config reload failed: %s", err.Error())
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/yaoapp/kun/log"
+)
+
+// WebhookEvent 启动事件
+type WebhookEvent struct {
+	Event       string    `json:"event"` // started|reloaded
+	Version     string    `json:"version,omitempty"`
+	Fingerprint string    `json:"fingerprint"`
+	Instance    string    `json:"instance"`
+	Time        time.Time `json:"time"`
+}
+
+// NotifyStartup 向 YAO_STARTUP_WEBHOOK 发送 started 事件 (未设置时返回 nil)
+// 发送失败输出警告并返回错误, 调用方不应因此中止启动
+func NotifyStartup(ctx context.Context) error {
+	confMutex.RLock()
+	cfg := Conf
+	confMutex.RUnlock()
+	return cfg.notifyWebhook(ctx, "started")
+}
+
+// notifyReloaded 配置变更后发送 reloaded 事件 (不阻塞配置变更处理)
+func notifyReloaded(cfg Config) {
+	if cfg.StartupWebhook == "" {
+		return
+	}
+	go cfg.notifyWebhook(context.Background(), "reloaded")
+}
+
+// notifyWebhook 发送事件 (超时时间为 YAO_STARTUP_WEBHOOK_TIMEOUT)
+func (c Config) notifyWebhook(ctx context.Context, event string) error {
+	if c.StartupWebhook == "" {
+		return nil
+	}
+
+	instance, _ := os.Hostname()
+	body, err := json.Marshal(WebhookEvent{
+		Event:       event,
+		Version:     c.Version,
+		Fingerprint: c.Fingerprint(),
+		Instance:    instance,
+		Time:        currentClock().Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	timeout := c.StartupWebhookTimeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err = postWebhook(ctx, c.StartupWebhook, body)
+	if err != nil {
+		log.With(log.F{"event": event}).Warn("notify startup webhook failed: %s", err.Error())
+	}
+	return err
+}
+
+// postWebhook 发送 JSON 请求 (返回 2xx 状态码为成功)
+func postWebhook(ctx context.Context, address string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, address, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("%s returned %s", address, res.Status)
+	}
+	return nil
+}
+
+// validateWebhook 检查启动事件地址
+func (c Config) validateWebhook() error {
+	errs := Errors{}
+	if c.StartupWebhook != "" {
+		u, err := url.Parse(c.StartupWebhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("YAO_STARTUP_WEBHOOK must be an http(s) URL (got %q)", c.StartupWebhook))
+		}
+	}
+	if c.StartupWebhookTimeout < 0 {
+		errs = append(errs, fmt.Errorf("YAO_STARTUP_WEBHOOK_TIMEOUT must not be negative (got %s)", c.StartupWebhookTimeout))
+	}
+	return errs.Err()
+}
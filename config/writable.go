@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// validateWritable 检查日志目录及数据目录是否可写
+// 设置了备用日志地址时, 至少一个日志目录可写即可; 数据目录仅在启用 importer 模块 (读写上传文件) 时检查
+// 检查会在目录中创建临时文件, 因此仅在完整校验 (Validate) 时执行, 包初始化时不执行
+func (c Config) validateWritable() error {
+	errs := Errors{}
+
+	logErrs := Errors{}
+	files := 0
+	for _, dest := range c.logDestinations() {
+		d, err := ParseLogDestination(dest)
+		if err != nil || d.Kind != LogDestFile {
+			continue
+		}
+		files++
+		logfile, err := filepath.Abs(d.Path)
+		if err != nil {
+			logErrs = append(logErrs, err)
+			continue
+		}
+		logErrs.Add(checkWritable(filepath.Dir(logfile)))
+	}
+	if files > 0 && len(logErrs) == files {
+		errs = append(errs, logErrs...)
+	}
+
+	if c.ModuleEnabled("importer") {
+		errs.Add(checkWritable(c.RootOf("data")))
+	}
+	return errs.Err()
+}
+
+// checkWritable 在目录中创建并删除临时文件, 检查目录是否可写 (目录不存在时检查最近的上级目录)
+func checkWritable(dir string) error {
+	existing := dir
+	for {
+		info, err := os.Stat(existing)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("directory %s is not writable: %s is not a directory", dir, existing)
+			}
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		existing = parent
+	}
+
+	file, err := os.CreateTemp(existing, ".yao-write-check-*")
+	if err != nil {
+		return fmt.Errorf("directory %s is not writable: %s", dir, err.Error())
+	}
+	file.Close()
+	os.Remove(file.Name())
+	return nil
+}
@@ -56,43 +56,66 @@ func Load(cfg config.Config) (err error) {
 	if err != nil {
 		log.Warn(err.Error())
 	}
-	err = script.Load(cfg) // 加载JS处理器 script
-	if err != nil {
-		log.Warn(err.Error())
+	if cfg.ModuleEnabled("script") {
+		err = script.Load(cfg) // 加载JS处理器 script
+		if err != nil {
+			log.Warn(err.Error())
+		}
 	}
 
-	// 第五步: 加载数据模型等
-	err = model.Load(cfg) // 加载数据模型 model
-	if err != nil {
-		log.Warn(err.Error())
+	// 第五步: 加载数据模型等 (跳过已禁用的模块)
+	if cfg.ModuleEnabled("model") {
+		err = model.Load(cfg) // 加载数据模型 model
+		if err != nil {
+			log.Warn(err.Error())
+		}
 	}
 
-	err = flow.Load(cfg) // 加载业务逻辑 Flow
-	if err != nil {
-		log.Warn(err.Error())
+	if cfg.ModuleEnabled("flow") {
+		err = flow.Load(cfg) // 加载业务逻辑 Flow
+		if err != nil {
+			log.Warn(err.Error())
+		}
 	}
-	err = plugin.Load(cfg) // 加载业务插件 plugin
-	if err != nil {
-		log.Warn(err.Error())
+
+	if cfg.ModuleEnabled("plugin") {
+		err = plugin.Load(cfg) // 加载业务插件 plugin
+		if err != nil {
+			log.Warn(err.Error())
+		}
 	}
 
-	err = table.Load(cfg) // 加载数据表格 table
-	if err != nil {
-		log.Warn(err.Error())
+	if cfg.ModuleEnabled("table") {
+		err = table.Load(cfg) // 加载数据表格 table
+		if err != nil {
+			log.Warn(err.Error())
+		}
 	}
 
-	err = chart.Load(cfg) // 加载分析图表 chart
-	if err != nil {
-		log.Warn(err.Error())
+	if cfg.ModuleEnabled("chart") {
+		err = chart.Load(cfg) // 加载分析图表 chart
+		if err != nil {
+			log.Warn(err.Error())
+		}
 	}
 
-	page.Load(cfg)     // 加载页面 page 忽略错误
-	importer.Load(cfg) // 加载数据导入 imports
-	workflow.Load(cfg) // 加载工作流  workflow
+	if cfg.ModuleEnabled("page") {
+		page.Load(cfg) // 加载页面 page 忽略错误
+	}
 
-	err = api.Load(cfg) // 加载业务接口 API
-	if err != nil {
-		log.Warn(err.Error())
+	if cfg.ModuleEnabled("importer") {
+		importer.Load(cfg) // 加载数据导入 imports
+	}
+
+	if cfg.ModuleEnabled("workflow") {
+		workflow.Load(cfg) // 加载工作流  workflow
+	}
+
+	if cfg.ModuleEnabled("api") {
+		err = api.Load(cfg) // 加载业务接口 API
+		if err != nil {
+			log.Warn(err.Error())
+		}
 	}
 
 	server.Load(cfg) // 加载服务
@@ -27,10 +27,36 @@ type JwtToken struct {
 	ExpiresAt int64  `json:"expires_at"`
 }
 
+// Valid 校验令牌有效期 (过期时间, 生效时间及签发时间均放宽 YAO_CLOCK_SKEW)
+func (claims JwtClaims) Valid() error {
+	cfg := config.Current()
+	err := new(jwt.ValidationError)
+	if claims.ExpiresAt != 0 {
+		if e := cfg.CheckValidity(time.Time{}, time.Unix(claims.ExpiresAt, 0)); e != nil {
+			err.Inner = fmt.Errorf("token is %s", e.Error())
+			err.Errors |= jwt.ValidationErrorExpired
+		}
+	}
+	if claims.NotBefore != 0 {
+		if e := cfg.CheckValidity(time.Unix(claims.NotBefore, 0), time.Time{}); e != nil {
+			err.Inner = fmt.Errorf("token is %s", e.Error())
+			err.Errors |= jwt.ValidationErrorNotValidYet
+		}
+	}
+	if claims.IssuedAt != 0 && cfg.CheckValidity(time.Unix(claims.IssuedAt, 0), time.Time{}) != nil {
+		err.Inner = fmt.Errorf("token used before issued")
+		err.Errors |= jwt.ValidationErrorIssuedAt
+	}
+	if err.Errors == 0 {
+		return nil
+	}
+	return err
+}
+
 // JwtValidate JWT 校验
 func JwtValidate(tokenString string) *JwtClaims {
 	token, err := jwt.ParseWithClaims(tokenString, &JwtClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(config.Conf.JWTSecret), nil
+		return []byte(config.Current().JWTSecret), nil
 	})
 
 	if err != nil {
@@ -96,7 +122,7 @@ func JwtMake(id int, data map[string]interface{}, option map[string]interface{})
 		},
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(config.Conf.JWTSecret))
+	tokenString, err := token.SignedString([]byte(config.Current().JWTSecret))
 	if err != nil {
 		exception.New("生成令牌失败", 500).Ctx(err).Throw()
 	}
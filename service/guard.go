@@ -18,8 +18,7 @@ func bearerJWT(c *gin.Context) {
 	tokenString := c.Request.Header.Get("Authorization")
 	tokenString = strings.TrimSpace(strings.TrimPrefix(tokenString, "Bearer "))
 	if tokenString == "" {
-		c.JSON(403, gin.H{"code": 403, "message": "无权访问该页面"})
-		c.Abort()
+		AbortWithError(c, 403, "无权访问该页面")
 		return
 	}
 
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yaoapp/yao/config"
+)
+
+// healthPath 健康检查路径 (设置 YAO_BASE_PATH 时位于该路径前缀下)
+const healthPath = "/health"
+
+// Health 健康检查 (GET /health): 返回 YAO_HEALTH_CHECKS 各检查项结果及版本信息
+// 服务未就绪 (如正在关闭) 或任一检查项失败时返回 503, 可用作负载均衡的就绪检查
+func Health(c *gin.Context) {
+	cfg := config.Current()
+	status := http.StatusOK
+	if !Ready() {
+		status = http.StatusServiceUnavailable
+	}
+
+	checks := map[string]string{}
+	for name, err := range cfg.HealthCheck() {
+		if err != nil {
+			checks[name] = err.Error()
+			status = http.StatusServiceUnavailable
+			continue
+		}
+		checks[name] = "ok"
+	}
+
+	c.JSON(status, gin.H{
+		"status":  http.StatusText(status),
+		"ready":   Ready(),
+		"checks":  checks,
+		"version": cfg.VersionInfo(),
+	})
+}
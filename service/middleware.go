@@ -1,11 +1,20 @@
 package service
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"path/filepath"
+	"runtime/debug"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yaoapp/kun/log"
 	"github.com/yaoapp/yao/config"
 	"github.com/yaoapp/yao/data"
 )
@@ -19,26 +28,211 @@ var AppFileServer http.Handler = http.FileServer(http.Dir(filepath.Join(config.C
 // Middlewares 服务中间件
 var Middlewares = []gin.HandlerFunc{
 	// BindDomain,
+	AwaitReload,
+	RequestID,
+	ConcurrencyLimit,
+	Timeout,
+	Recovery,
+	LogBodies,
 	BinStatic,
 }
 
-// BinStatic 静态文件服务
+// bodyWriter 记录响应内容 (最多记录 max 字节)
+type bodyWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+	max  int64
+}
+
+func (w *bodyWriter) Write(data []byte) (int, error) {
+	if remain := w.max - int64(w.body.Len()); remain > 0 {
+		if int64(len(data)) > remain {
+			w.body.Write(data[:remain])
+		} else {
+			w.body.Write(data)
+		}
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// LogBodies 记录请求及响应内容 (YAO_LOG_BODIES, 仅记录配置的路径, 内容按最大字节数截断)
+func LogBodies(c *gin.Context) {
+	enabled, max := config.Current().LogBodyFor(c.Request.URL.Path)
+	if !enabled {
+		c.Next()
+		return
+	}
+
+	request := []byte{}
+	if c.Request.Body != nil {
+		data, err := io.ReadAll(io.LimitReader(c.Request.Body, max))
+		if err == nil {
+			request = data
+		}
+		c.Request.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(request), c.Request.Body), c.Request.Body}
+	}
+
+	writer := &bodyWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}, max: max}
+	c.Writer = writer
+	c.Next()
+
+	log.With(log.F{
+		"method":   c.Request.Method,
+		"path":     c.Request.URL.Path,
+		"status":   writer.Status(),
+		"request":  string(request),
+		"response": writer.body.String(),
+	}).Info("request body")
+}
+
+// Recovery 请求处理 panic 时返回 500 (是否输出调用栈及返回错误信息由配置决定)
+func Recovery(c *gin.Context) {
+	defer func() {
+		err := recover()
+		if err == nil {
+			return
+		}
+
+		stack, expose := config.Current().RecoverPolicy()
+		fields := log.F{"path": c.Request.URL.Path, "method": c.Request.Method}
+		if stack {
+			fields["stack"] = string(debug.Stack())
+		}
+		log.With(fields).Error("%v", err)
+
+		message := "Internal Server Error"
+		if expose {
+			message = fmt.Sprintf("%v", err)
+		}
+		AbortWithError(c, 500, message)
+	}()
+	c.Next()
+}
+
+// AbortWithError 按配置的错误响应格式 (YAO_ERROR_FORMAT) 返回错误并中止请求
+func AbortWithError(c *gin.Context, status int, message string) {
+	contentType, body := config.Current().ErrorResponder()(status, message)
+	c.Data(status, contentType, body)
+	c.Abort()
+}
+
+// AwaitReload 应用中断性配置变更 (如更换证书) 期间暂停处理新请求
+func AwaitReload(c *gin.Context) {
+	config.AwaitReload()
+	c.Next()
+}
+
+// RequestID 请求 ID (请求头名称及生成策略由配置决定)
+func RequestID(c *gin.Context) {
+	cfg := config.Current()
+	header := cfg.RequestIDHeaderName()
+	id := cfg.RequestID(c.Request.Header.Get(header))
+	c.Set("__request_id", id)
+	c.Writer.Header().Set(header, id)
+	c.Next()
+}
+
+// Timeout 设置请求处理截止时间 (YAO_REQUEST_TIMEOUT, 按路径前缀使用 YAO_ROUTE_TIMEOUTS), 超时且未响应时返回 504
+// 仅为请求 context 设置截止时间, 不限制处理时间: 不检查 context 的处理程序 (如多数 gou 处理器) 会执行完成, 504 在处理程序返回后写入
+// 不使用 http.TimeoutHandler, 以免缓存响应导致 websocket 升级及流式响应不可用
+func Timeout(c *gin.Context) {
+	timeout := config.Current().RouteTimeout(c.Request.URL.Path)
+	if timeout <= 0 {
+		c.Next()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+	c.Request = c.Request.WithContext(ctx)
+	c.Next()
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+		AbortWithError(c, 504, "Gateway Timeout")
+	}
+}
+
+// limiter 同时处理请求数的信号量 (首次请求时按配置创建, 修改 YAO_MAX_CONCURRENT_REQUESTS 需要重启服务)
+var limiter chan struct{}
+var limiterOnce sync.Once
+
+// ConcurrencyLimit 限制同时处理的请求数 (YAO_MAX_CONCURRENT_REQUESTS), 已满时等待 YAO_CONCURRENCY_WAIT 后仍无空闲则返回 503
+func ConcurrencyLimit(c *gin.Context) {
+	cfg := config.Current()
+	limiterOnce.Do(func() { limiter = cfg.ConcurrencyLimiter() })
+	if limiter == nil {
+		c.Next()
+		return
+	}
+
+	select {
+	case limiter <- struct{}{}:
+	default:
+		wait := cfg.ConcurrencyWait
+		if wait <= 0 {
+			AbortWithError(c, 503, "Service Unavailable")
+			return
+		}
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case limiter <- struct{}{}:
+		case <-timer.C:
+			AbortWithError(c, 503, "Service Unavailable")
+			return
+		case <-c.Request.Context().Done():
+			c.Abort()
+			return
+		}
+	}
+
+	defer func() { <-limiter }()
+	c.Next()
+}
+
+// staticPrefixes YAO_STATIC_MOUNTS 挂载的路径前缀 (由对应的路由处理)
+var staticPrefixes = []string{}
+
+// BinStatic 静态文件服务 (设置 YAO_BASE_PATH 时, 仅处理该路径前缀下的请求)
 func BinStatic(c *gin.Context) {
 
-	length := len(c.Request.URL.Path)
+	path := c.Request.URL.Path
+	if base := config.Current().BasePath(); base != "" {
+		if path != base && !strings.HasPrefix(path, base+"/") {
+			c.Next()
+			return
+		}
+		path = strings.TrimPrefix(path, base)
+		if path == "" {
+			path = "/"
+		}
+	}
+
+	for _, prefix := range staticPrefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			c.Next()
+			return
+		}
+	}
+
+	length := len(path)
 
-	if (length >= 5 && c.Request.URL.Path[0:5] == "/api/") ||
-		(length >= 11 && c.Request.URL.Path[0:11] == "/websocket/") { // API & websocket
+	if (length >= 5 && path[0:5] == "/api/") ||
+		(length >= 11 && path[0:11] == "/websocket/") || path == healthPath { // API & websocket & 健康检查
 		c.Next()
 		return
-	} else if length >= 7 && c.Request.URL.Path[0:7] == "/xiang/" { // 数据管理后台
-		c.Request.URL.Path = strings.TrimPrefix(c.Request.URL.Path, "/xiang")
+	} else if length >= 7 && path[0:7] == "/xiang/" { // 数据管理后台
+		c.Request.URL.Path = strings.TrimPrefix(path, "/xiang")
 		AdminFileServer.ServeHTTP(c.Writer, c.Request)
 		c.Abort()
 		return
 	}
 
 	// 应用内静态文件目录(/ui)
+	c.Request.URL.Path = path
 	AppFileServer.ServeHTTP(c.Writer, c.Request)
 	c.Abort()
 }
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yaoapp/gou"
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/yao/config"
+)
+
+// ready 服务是否就绪 (开始关闭服务时设为未就绪, 供负载均衡摘除流量)
+var ready int32
+
+// serving 服务是否在运行 (启动失败或已关闭时为 0, 关闭服务时不等待)
+var serving int32
+
+// Ready 服务是否就绪
+func Ready() bool {
+	return atomic.LoadInt32(&ready) == 1
+}
+
+// newRouter 根据配置创建路由
+func newRouter(cfg *config.Config) *gin.Engine {
+	router := gin.New()
+	router.MaxMultipartMemory = cfg.MultipartMaxMemory()
+	router.RedirectTrailingSlash = cfg.TrailingSlashPolicy() == "redirect"
+	router.Use(Middlewares...)
+	router.GET(cfg.Route(healthPath), Health)
+	gou.SetHTTPRoutes(router, cfg.Route("/api"))
+
+	mounts, err := cfg.StaticMounts()
+	if err != nil {
+		log.Error("static mounts: %s", err.Error())
+	}
+	staticPrefixes = []string{}
+	for _, mount := range mounts {
+		router.Static(cfg.Route(mount.Prefix), mount.Dir)
+		staticPrefixes = append(staticPrefixes, mount.Prefix)
+	}
+	return router
+}
+
+// trimTrailingSlash 路由匹配前去除请求路径结尾的 / (YAO_TRAILING_SLASH=ignore)
+func trimTrailingSlash(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path := r.URL.Path; len(path) > 1 && strings.HasSuffix(path, "/") {
+			r.URL.Path = strings.TrimRight(path, "/")
+			if r.URL.Path == "" {
+				r.URL.Path = "/"
+			}
+			r.URL.RawPath = ""
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// newServer 根据配置创建 HTTP 服务 (HTTPS 使用 TLSConfig, 证书可热更新)
+func newServer(cfg *config.Config, handler http.Handler) (*http.Server, error) {
+	tlsConfig, err := cfg.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	return &http.Server{
+		Addr:           cfg.Addr(),
+		Handler:        handler,
+		MaxHeaderBytes: cfg.MaxHeaderBytes(),
+		TLSConfig:      tlsConfig,
+	}, nil
+}
+
+// serve 启动 HTTP 服务, 收到关闭信号后关闭服务
+// 创建服务 (如证书错误) 或绑定监听地址失败时立即返回错误
+func serve() error {
+	cfg := config.Current()
+	gou.SetHTTPGuards(Guards)
+
+	var handler http.Handler = newRouter(cfg)
+	if cfg.TrailingSlashPolicy() == "ignore" {
+		handler = trimTrailingSlash(handler)
+	}
+
+	srv, err := newServer(cfg, handler)
+	if err == nil {
+		err = listen(srv)
+	}
+	if err != nil {
+		log.Error("start service failed: %s", err.Error())
+		return err
+	}
+	atomic.StoreInt32(&serving, 1)
+
+	<-shutdown
+	drain(srv, config.Current())
+	shutdownComplete <- true
+	return nil
+}
+
+// stopServe 发送关闭信号并等待服务关闭 (服务未运行时直接返回)
+func stopServe() {
+	if !atomic.CompareAndSwapInt32(&serving, 1, 0) {
+		return
+	}
+	shutdown <- true
+	<-shutdownComplete
+}
+
+// listen 绑定监听地址并在后台处理请求 (绑定成功后发送启动事件)
+func listen(srv *http.Server) error {
+	listener, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		var err error
+		if srv.TLSConfig != nil {
+			err = srv.ServeTLS(listener, "", "")
+		} else {
+			err = srv.Serve(listener)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("service stopped: %s", err.Error())
+		}
+	}()
+
+	atomic.StoreInt32(&ready, 1)
+	go config.NotifyStartup(context.Background())
+	return nil
+}
+
+// drain 关闭服务: 设为未就绪, 等待 DrainDelay 后停止接收新连接, 最多等待 DrainTimeout 处理中的请求完成
+func drain(srv *http.Server, cfg *config.Config) {
+	atomic.StoreInt32(&ready, 0)
+	delay, timeout := cfg.DrainPolicy()
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Warn("service shutdown: %s, close the remaining connections", err.Error())
+		srv.Close()
+	}
+}
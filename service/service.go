@@ -9,54 +9,32 @@ import (
 var shutdown = make(chan bool)
 var shutdownComplete = make(chan bool)
 
-// Start 启动服务
-func Start() {
+// Start 启动服务 (阻塞至服务关闭, 服务启动失败时返回错误)
+func Start() error {
 
 	if config.Conf.Session.Hosting && config.Conf.Session.IsCLI == false {
 		share.SessionServerStart()
 	}
 
-	gou.SetHTTPGuards(Guards)
-	gou.ServeHTTP(
-		gou.Server{
-			Host: config.Conf.Host,
-			Port: config.Conf.Port,
-			Root: "/api",
-		},
-		&shutdown, func(s gou.Server) {
-			shutdownComplete <- true
-		},
-		Middlewares...)
+	return serve()
 }
 
-// StartWithouttSession 启动服务
-func StartWithouttSession() {
-
-	gou.SetHTTPGuards(Guards)
-	gou.ServeHTTP(
-		gou.Server{
-			Host: config.Conf.Host,
-			Port: config.Conf.Port,
-			Root: "/api",
-		},
-		&shutdown, func(s gou.Server) {
-			shutdownComplete <- true
-		},
-		Middlewares...)
+// StartWithouttSession 启动服务 (阻塞至服务关闭, 服务启动失败时返回错误)
+func StartWithouttSession() error {
+
+	return serve()
 }
 
 // StopWithouttSession 关闭服务
 func StopWithouttSession(onComplete func()) {
-	shutdown <- true
-	<-shutdownComplete
+	stopServe()
 	gou.KillPlugins()
 	onComplete()
 }
 
 // Stop 关闭服务
 func Stop(onComplete func()) {
-	shutdown <- true
-	<-shutdownComplete
+	stopServe()
 	share.SessionServerStop()
 	gou.KillPlugins()
 	onComplete()
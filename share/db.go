@@ -1,18 +1,25 @@
 package share
 
 import (
+	"context"
 	"time"
 
 	"github.com/yaoapp/xun/capsule"
 	"github.com/yaoapp/yao/config"
 )
 
+func init() {
+	config.RegisterHealthCheck("db", func(ctx context.Context) error {
+		return capsule.Query().DB().PingContext(ctx)
+	})
+}
+
 // DBConnect 建立数据库连接
 func DBConnect(dbconfig config.DBConfig) {
 
 	// 连接主库
 	for i, dsn := range dbconfig.Primary {
-		db := capsule.AddConn("primary", dbconfig.Driver, dsn, 5*time.Second)
+		db := capsule.AddConn("primary", dbconfig.Driver, dbconfig.SQLiteDSN(dsn), 5*time.Second)
 		if i == 0 {
 			db.SetAsGlobal()
 		}
@@ -20,6 +27,6 @@ func DBConnect(dbconfig config.DBConfig) {
 
 	// 连接从库
 	for _, dsn := range dbconfig.Secondary {
-		capsule.AddReadConn("secondary", dbconfig.Driver, dsn, 5*time.Second)
+		capsule.AddReadConn("secondary", dbconfig.Driver, dbconfig.SQLiteDSN(dsn), 5*time.Second)
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
 
 	klog "github.com/yaoapp/kun/log"
 
@@ -26,6 +27,13 @@ var SessionPort int
 func init() {
 	SessionPort = network.FreePort()
 	klog.Trace("session port: %d", SessionPort)
+	config.RegisterHealthCheck("session", func(ctx context.Context) error {
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", fmt.Sprintf("%s:%d", "127.0.0.1", SessionPort))
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	})
 }
 
 // SessionConnect 加载会话信息